@@ -4,15 +4,153 @@ import (
 	"time"
 )
 
-func bucket(t time.Time, step time.Duration) time.Time {
-	return bucketIter{
-		v:    t,
-		step: step}.Bucket()
+// Bucketing splits the time of a time series into buckets, the rows of every
+// bucket being stored in their own partition. The bucket preceding the one
+// starting at b is the bucket containing the instant before b.
+type Bucketing interface {
+	// Bucket returns the start of the bucket containing t
+	Bucket(t time.Time) time.Time
+	// Next returns the start of the bucket following the one starting at bucket
+	Next(bucket time.Time) time.Time
+}
+
+// tableBucketing returns the bucketing of a time series table, which is set
+// with the Bucketing option of the table, or else has buckets of the size the
+// table was created with
+func tableBucketing(t Table, size time.Duration) Bucketing {
+	if b := t.Options().Bucketing; b != nil {
+		return b
+	}
+	return FixedBuckets(size)
+}
+
+type fixedBuckets time.Duration
+
+// FixedBuckets returns a bucketing with buckets of the given size, aligned to
+// the Unix epoch. Sizes are truncated to whole seconds, and are at least a
+// second. This is the bucketing of time series tables by default.
+func FixedBuckets(size time.Duration) Bucketing {
+	return fixedBuckets(size)
+}
+
+func (b fixedBuckets) step() time.Duration {
+	step := time.Duration(b).Truncate(time.Second)
+	if step < time.Second {
+		step = time.Second
+	}
+	return step
+}
+
+func (b fixedBuckets) Bucket(t time.Time) time.Time {
+	secs := t.Unix()
+	return time.Unix((secs - secs%int64(b.step()/time.Second)), 0)
+}
+
+func (b fixedBuckets) Next(bucket time.Time) time.Time {
+	return b.Bucket(bucket.Add(b.step()))
+}
+
+type calendarUnit int
+
+const (
+	calendarDay calendarUnit = iota
+	calendarWeek
+	calendarMonth
+)
+
+type calendarBuckets struct {
+	unit calendarUnit
+	loc  *time.Location
+}
+
+// DayBuckets returns a bucketing with a bucket per calendar day in the given
+// location, or UTC if it's nil. Days are 23 or 25 hours long when daylight
+// saving time starts or ends.
+func DayBuckets(loc *time.Location) Bucketing {
+	return newCalendarBuckets(calendarDay, loc)
+}
+
+// WeekBuckets returns a bucketing with a bucket per calendar week in the
+// given location, or UTC if it's nil. Weeks start on Monday.
+func WeekBuckets(loc *time.Location) Bucketing {
+	return newCalendarBuckets(calendarWeek, loc)
+}
+
+// MonthBuckets returns a bucketing with a bucket per calendar month in the
+// given location, or UTC if it's nil.
+func MonthBuckets(loc *time.Location) Bucketing {
+	return newCalendarBuckets(calendarMonth, loc)
+}
+
+func newCalendarBuckets(unit calendarUnit, loc *time.Location) Bucketing {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return calendarBuckets{unit: unit, loc: loc}
+}
+
+func (b calendarBuckets) Bucket(t time.Time) time.Time {
+	y, m, d := t.In(b.loc).Date()
+	switch b.unit {
+	case calendarWeek:
+		// Weekdays count from Sunday, weeks start on Monday
+		day := time.Date(y, m, d, 0, 0, 0, 0, b.loc)
+		return time.Date(y, m, d-(int(day.Weekday())+6)%7, 0, 0, 0, 0, b.loc)
+	case calendarMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, b.loc)
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, b.loc)
+}
+
+func (b calendarBuckets) Next(bucket time.Time) time.Time {
+	y, m, d := bucket.In(b.loc).Date()
+	switch b.unit {
+	case calendarWeek:
+		return b.Bucket(time.Date(y, m, d+7, 0, 0, 0, 0, b.loc))
+	case calendarMonth:
+		return time.Date(y, m+1, 1, 0, 0, 0, 0, b.loc)
+	}
+	return b.Bucket(time.Date(y, m, d+1, 0, 0, 0, 0, b.loc))
+}
+
+type scheduledBuckets struct {
+	before  Bucketing
+	cutOver time.Time
+	after   Bucketing
+}
+
+// ScheduledBuckets returns a bucketing which changes at the cut-over, using
+// the before bucketing for earlier times and the after bucketing from then
+// on. The first bucket after the cut-over starts at the cut-over, and the
+// last one before it ends there. Schedules with several cut-overs can be made
+// by nesting ScheduledBuckets.
+func ScheduledBuckets(before Bucketing, cutOver time.Time, after Bucketing) Bucketing {
+	return scheduledBuckets{before: before, cutOver: cutOver, after: after}
+}
+
+func (b scheduledBuckets) Bucket(t time.Time) time.Time {
+	if t.Before(b.cutOver) {
+		return b.before.Bucket(t)
+	}
+	if bucket := b.after.Bucket(t); !bucket.Before(b.cutOver) {
+		return bucket
+	}
+	return b.cutOver
+}
+
+func (b scheduledBuckets) Next(bucket time.Time) time.Time {
+	if !bucket.Before(b.cutOver) {
+		return b.after.Next(b.after.Bucket(bucket))
+	}
+	if next := b.before.Next(bucket); next.Before(b.cutOver) {
+		return next
+	}
+	return b.cutOver
 }
 
 type bucketIter struct {
 	v         time.Time
-	step      time.Duration
+	bucketing Bucketing
 	field     string
 	invariant Filter
 }
@@ -22,26 +160,21 @@ func (b bucketIter) String() string {
 }
 
 func (b bucketIter) Bucket() time.Time {
-	step := b.step
-	if step < time.Second {
-		step = time.Second
-	}
-	secs := b.v.Unix()
-	return time.Unix((secs - secs%int64(step/time.Second)), 0)
+	return b.bucketing.Bucket(b.v)
 }
 
 func (b bucketIter) Next() Buckets {
 	return bucketIter{
-		v:         b.v.Add(b.step),
-		step:      b.step,
+		v:         b.bucketing.Next(b.Bucket()),
+		bucketing: b.bucketing,
 		invariant: b.invariant,
 		field:     b.field}
 }
 
 func (b bucketIter) Prev() Buckets {
 	return bucketIter{
-		v:         b.v.Add(-b.step),
-		step:      b.step,
+		v:         b.Bucket().Add(-time.Nanosecond),
+		bucketing: b.bucketing,
 		invariant: b.invariant,
 		field:     b.field}
 }
@@ -0,0 +1,61 @@
+package gocassa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedBuckets(t *testing.T) {
+	b := FixedBuckets(time.Hour)
+	tim := time.Date(2020, 3, 4, 15, 4, 5, 6, time.UTC)
+	assert.True(t, time.Date(2020, 3, 4, 15, 0, 0, 0, time.UTC).Equal(b.Bucket(tim)))
+	assert.True(t, time.Date(2020, 3, 4, 16, 0, 0, 0, time.UTC).Equal(b.Next(b.Bucket(tim))))
+
+	// Sizes below a second are rounded up to a second
+	b = FixedBuckets(time.Millisecond)
+	assert.True(t, time.Date(2020, 3, 4, 15, 4, 5, 0, time.UTC).Equal(b.Bucket(tim)))
+	assert.True(t, time.Date(2020, 3, 4, 15, 4, 6, 0, time.UTC).Equal(b.Next(b.Bucket(tim))))
+}
+
+func TestCalendarBuckets(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	// 00:30 in London is still the previous day in UTC during summer time
+	tim := time.Date(2021, 3, 28, 23, 30, 0, 0, time.UTC)
+	day := DayBuckets(loc)
+	assert.Equal(t, time.Date(2021, 3, 29, 0, 0, 0, 0, loc), day.Bucket(tim))
+	assert.Equal(t, time.Date(2021, 3, 30, 0, 0, 0, 0, loc), day.Next(day.Bucket(tim)))
+	// The day daylight saving time starts is 23 hours long
+	assert.Equal(t, 23*time.Hour, day.Next(time.Date(2021, 3, 28, 0, 0, 0, 0, loc)).Sub(time.Date(2021, 3, 28, 0, 0, 0, 0, loc)))
+
+	week := WeekBuckets(nil)
+	assert.Equal(t, time.Date(2021, 3, 22, 0, 0, 0, 0, time.UTC), week.Bucket(time.Date(2021, 3, 28, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2021, 3, 29, 0, 0, 0, 0, time.UTC), week.Bucket(time.Date(2021, 3, 29, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2021, 4, 5, 0, 0, 0, 0, time.UTC), week.Next(time.Date(2021, 3, 29, 0, 0, 0, 0, time.UTC)))
+
+	month := MonthBuckets(nil)
+	assert.Equal(t, time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC), month.Bucket(time.Date(2021, 12, 31, 23, 59, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), month.Next(time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestScheduledBuckets(t *testing.T) {
+	cutOver := time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+	b := ScheduledBuckets(FixedBuckets(24*time.Hour), cutOver, MonthBuckets(nil))
+
+	assert.True(t, time.Date(2021, 3, 9, 0, 0, 0, 0, time.UTC).Equal(b.Bucket(time.Date(2021, 3, 9, 1, 0, 0, 0, time.UTC))))
+	assert.True(t, time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC).Equal(b.Bucket(time.Date(2021, 3, 10, 11, 0, 0, 0, time.UTC))))
+	// The first bucket after the cut-over starts at the cut-over
+	assert.Equal(t, cutOver, b.Bucket(time.Date(2021, 3, 20, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC), b.Bucket(time.Date(2021, 4, 20, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, cutOver, b.Next(time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC), b.Next(cutOver))
+
+	iter := bucketIter{v: time.Date(2021, 4, 20, 0, 0, 0, 0, time.UTC), bucketing: b}
+	assert.Equal(t, cutOver, iter.Prev().Bucket())
+	assert.True(t, time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC).Equal(iter.Prev().Prev().Bucket()))
+}
@@ -0,0 +1,391 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxInQueryBuckets is the largest number of buckets AutoBucketQuery
+	// reads with a single IN query
+	maxInQueryBuckets = 16
+	// defaultBucketParallelism is the number of buckets ParallelBucketQuery
+	// reads at a time if Options.BucketParallelism isn't set
+	defaultBucketParallelism = 4
+)
+
+// bucketsOp reads the rows of a time range of a time series, which is split
+// into buckets. Depending on the BucketQuery option, it reads all buckets at
+// once with an IN relation on the bucket, or reads them bucket by bucket and
+// merges the results in clustering order, stopping once it has read enough
+// rows.
+type bucketsOp struct {
+	options   Options
+	from      Buckets    // bucket of the start of the range
+	to        Buckets    // bucket of the end of the range
+	end       time.Time  // buckets starting at or after end aren't read
+	relations []Relation // relations applied within every bucket
+	// latestField reads the buckets newest first, in descending order of
+	// the field, which is how the latest rows are listed
+	latestField string
+	// shards splits every bucket into partitions by the shardField, which
+	// are read separately and merged in the order of the sortField
+	shardField string
+	shards     int
+	sortField  string
+	limit      int // maximum number of rows to read, overriding the Limit option
+	result     interface{}
+}
+
+func newBucketsOp(from, to Buckets, end time.Time, relations []Relation, pointerToASlice interface{}) *bucketsOp {
+	return &bucketsOp{
+		from:      from,
+		to:        to,
+		end:       end,
+		relations: relations,
+		result:    pointerToASlice,
+	}
+}
+
+func newLatestOp(from, to Buckets, timeField string, start, before time.Time, limit int, pointerToASlice interface{}) *bucketsOp {
+	op := newBucketsOp(from, to, before, []Relation{GTE(timeField, start), LT(timeField, before)}, pointerToASlice)
+	op.latestField = timeField
+	op.limit = limit
+	return op
+}
+
+// withShards reads every bucket from the given number of shards, merging
+// their rows in the order of the sort field
+func (o *bucketsOp) withShards(shardField string, shards int, sortField string) *bucketsOp {
+	o.shardField = shardField
+	o.shards = shards
+	o.sortField = sortField
+	return o
+}
+
+// mergedOptions returns the options of the table merged with the ones of
+// the op
+func (o *bucketsOp) mergedOptions() Options {
+	return o.from.Filter().Table().Options().Merge(o.options)
+}
+
+// strategy returns the way the buckets are read. The latest rows, and the
+// rows of sharded buckets, are always read bucket by bucket.
+func (o *bucketsOp) strategy(opt Options) BucketQuery {
+	atOnce := o.latestField == "" && o.shards == 0
+	switch opt.BucketQuery {
+	case InBucketQuery:
+		if atOnce {
+			return InBucketQuery
+		}
+	case SequentialBucketQuery, ParallelBucketQuery:
+		return opt.BucketQuery
+	}
+
+	if atOnce && len(o.bucketsAfter(o.from, maxInQueryBuckets+1)) <= maxInQueryBuckets {
+		return InBucketQuery
+	}
+	return SequentialBucketQuery
+}
+
+// descending returns whether the buckets are read newest first, which is the
+// case when the rows are read in descending clustering order
+func (o *bucketsOp) descending(opt Options) bool {
+	return o.latestField != "" || (len(opt.ClusteringOrder) > 0 && opt.ClusteringOrder[0].Direction == DESC)
+}
+
+// bucketsAfter returns up to n buckets of the range, in reading order,
+// starting with b
+func (o *bucketsOp) bucketsAfter(b Buckets, n int) []Buckets {
+	buckets := []Buckets{}
+	for ; len(buckets) < n && o.contains(b); b = b.Next() {
+		buckets = append(buckets, b)
+	}
+	return buckets
+}
+
+// bucketsBefore returns up to n buckets of the range, in descending order,
+// starting with b
+func (o *bucketsOp) bucketsBefore(b Buckets, n int) []Buckets {
+	buckets := []Buckets{}
+	if !b.Bucket().Before(o.end) {
+		b = b.Prev()
+	}
+	for ; len(buckets) < n && o.contains(b); b = b.Prev() {
+		buckets = append(buckets, b)
+	}
+	return buckets
+}
+
+func (o *bucketsOp) contains(b Buckets) bool {
+	return !b.Bucket().Before(o.from.Bucket()) && b.Bucket().Before(o.end)
+}
+
+// inOp returns the op reading all buckets with a single query
+func (o *bucketsOp) inOp(result interface{}) Op {
+	buckets := []interface{}{}
+	for _, b := range o.bucketsAfter(o.from, int(^uint(0)>>1)) {
+		buckets = append(buckets, b.Bucket())
+	}
+
+	// The last relation of the filter of a bucket is the one on the bucket
+	f := o.from.Filter()
+	rels := append([]Relation{}, f.Relations()...)
+	rels[len(rels)-1] = In(rels[len(rels)-1].Field(), buckets...)
+	rels = append(rels, o.relations...)
+	return f.Table().Where(rels...).Read(result).WithOptions(o.options)
+}
+
+// bucketFilter returns the filter of a bucket, or of a shard of the bucket
+// if the buckets are sharded
+func (o *bucketsOp) bucketFilter(b Buckets, shard int) Filter {
+	f := b.Filter()
+	if o.shards == 0 {
+		return f
+	}
+	rels := []Relation{}
+	for _, rel := range f.Relations() {
+		if rel.Field() != o.shardField {
+			rels = append(rels, rel)
+		}
+	}
+	rels = append(rels, Eq(o.shardField, shard))
+	return f.Table().Where(rels...)
+}
+
+// bucketOp returns the op reading a bucket, or a shard of a bucket
+func (o *bucketsOp) bucketOp(f Filter, result interface{}, limit int) Op {
+	rels := append(append([]Relation{}, f.Relations()...), o.relations...)
+	opt := Options{Limit: limit}
+	if o.latestField != "" {
+		opt.ClusteringOrder = []ClusteringOrderColumn{{Column: o.latestField, Direction: DESC}}
+	}
+	return f.Table().Where(rels...).Read(result).WithOptions(o.options).WithOptions(opt)
+}
+
+// firstOp returns the op reading the first bucket, or all the buckets if
+// they are read with a single query
+func (o *bucketsOp) firstOp() Op {
+	opt := o.mergedOptions()
+	if o.strategy(opt) == InBucketQuery {
+		return o.inOp(o.result)
+	}
+	first := o.from
+	if o.descending(opt) {
+		if buckets := o.bucketsBefore(o.to, 1); len(buckets) > 0 {
+			first = buckets[0]
+		}
+	}
+	return o.bucketOp(o.bucketFilter(first, 0), o.result, o.limit)
+}
+
+func (o *bucketsOp) Run() error {
+	if err := o.Preflight(); err != nil {
+		return err
+	}
+
+	opt := o.mergedOptions()
+	strategy := o.strategy(opt)
+	if strategy == InBucketQuery {
+		return o.inOp(o.result).Run()
+	}
+
+	limit := o.limit
+	if limit == 0 {
+		limit = opt.Limit
+	}
+	parallelism := 1
+	if strategy == ParallelBucketQuery {
+		parallelism = opt.BucketParallelism
+		if parallelism <= 0 {
+			parallelism = defaultBucketParallelism
+		}
+	}
+
+	slice := reflect.ValueOf(o.result).Elem()
+	rows := reflect.MakeSlice(slice.Type(), 0, limit)
+	var buckets []Buckets
+	if o.descending(opt) {
+		buckets = o.bucketsBefore(o.to, parallelism)
+	} else {
+		buckets = o.bucketsAfter(o.from, parallelism)
+	}
+	for len(buckets) > 0 {
+		remaining := 0
+		if limit > 0 {
+			remaining = limit - rows.Len()
+		}
+		pages, err := o.readBuckets(buckets, slice.Type(), remaining, o.descending(opt))
+		if err != nil {
+			return err
+		}
+		for _, page := range pages {
+			rows = reflect.AppendSlice(rows, page)
+		}
+		if limit > 0 && rows.Len() >= limit {
+			rows = rows.Slice(0, limit)
+			break
+		}
+
+		last := buckets[len(buckets)-1]
+		if o.descending(opt) {
+			buckets = o.bucketsBefore(last.Prev(), parallelism)
+		} else {
+			buckets = o.bucketsAfter(last.Next(), parallelism)
+		}
+	}
+	slice.Set(rows)
+	return nil
+}
+
+// readBuckets reads the given buckets concurrently, and returns their rows
+// in the order of the buckets
+func (o *bucketsOp) readBuckets(buckets []Buckets, sliceType reflect.Type, limit int, descending bool) ([]reflect.Value, error) {
+	pages := make([]reflect.Value, len(buckets))
+	errs := make([]error, len(buckets))
+	wg := sync.WaitGroup{}
+	for i, b := range buckets {
+		wg.Add(1)
+		go func(i int, b Buckets) {
+			defer wg.Done()
+			pages[i], errs[i] = o.readBucket(b, sliceType, limit, descending)
+		}(i, b)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// readBucket reads the rows of a bucket. The shards of sharded buckets are
+// read concurrently, and their rows merged in the order of the sort field.
+func (o *bucketsOp) readBucket(b Buckets, sliceType reflect.Type, limit int, descending bool) (reflect.Value, error) {
+	if o.shards == 0 {
+		page := reflect.New(sliceType)
+		err := o.bucketOp(b.Filter(), page.Interface(), limit).Run()
+		return page.Elem(), err
+	}
+
+	shards := make([]reflect.Value, o.shards)
+	errs := make([]error, o.shards)
+	wg := sync.WaitGroup{}
+	for shard := range shards {
+		wg.Add(1)
+		go func(shard int) {
+			defer wg.Done()
+			page := reflect.New(sliceType)
+			errs[shard] = o.bucketOp(o.bucketFilter(b, shard), page.Interface(), limit).Run()
+			shards[shard] = page.Elem()
+		}(shard)
+	}
+	wg.Wait()
+
+	rows := reflect.MakeSlice(sliceType, 0, 0)
+	for shard, page := range shards {
+		if errs[shard] != nil {
+			return rows, errs[shard]
+		}
+		rows = reflect.AppendSlice(rows, page)
+	}
+	rows, err := sortRows(rows, o.sortField, descending)
+	if err != nil {
+		return rows, err
+	}
+	if limit > 0 && rows.Len() > limit {
+		rows = rows.Slice(0, limit)
+	}
+	return rows, nil
+}
+
+// sortRows sorts a slice of rows by the value of a field of the rows
+func sortRows(rows reflect.Value, field string, descending bool) (reflect.Value, error) {
+	keys := make([]interface{}, rows.Len())
+	for i := range keys {
+		m, ok := toMap(rows.Index(i).Interface())
+		if !ok {
+			return rows, fmt.Errorf("Can't sort rows of type %v", rows.Type().Elem())
+		}
+		keys[i] = convertToPrimitive(m[field])
+	}
+
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	var err error
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := keys[order[i]], keys[order[j]]
+		if descending {
+			a, b = b, a
+		}
+		less, lessErr := builtinLessThan(a, b)
+		if lessErr != nil {
+			err = lessErr
+		}
+		return less
+	})
+
+	sorted := reflect.MakeSlice(rows.Type(), 0, len(order))
+	for _, i := range order {
+		sorted = reflect.Append(sorted, rows.Index(i))
+	}
+	return sorted, err
+}
+
+func (o *bucketsOp) RunWithContext(ctx context.Context) error {
+	return o.WithOptions(Options{Context: ctx}).Run()
+}
+
+func (o *bucketsOp) RunAtomically() error {
+	return o.Run()
+}
+
+func (o *bucketsOp) RunLoggedBatchWithContext(ctx context.Context) error {
+	return o.WithOptions(Options{Context: ctx}).Run()
+}
+
+func (o *bucketsOp) RunAtomicallyWithContext(ctx context.Context) error {
+	return o.RunLoggedBatchWithContext(ctx)
+}
+
+func (o *bucketsOp) Add(additions ...Op) Op {
+	return multiOp{o}.Add(additions...)
+}
+
+func (o *bucketsOp) Options() Options {
+	return o.options
+}
+
+func (o *bucketsOp) WithOptions(opts Options) Op {
+	result := *o
+	result.options = o.options.Merge(opts)
+	return &result
+}
+
+func (o *bucketsOp) Preflight() error {
+	if o.limit < 0 {
+		return fmt.Errorf("Limit of the latest rows can't be negative, got %v", o.limit)
+	}
+	if v := reflect.ValueOf(o.result); v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Buckets can only be read into a pointer to a slice, got %T", o.result)
+	}
+	return nil
+}
+
+// GenerateStatement generates the statement reading all buckets if they are
+// read with a single query, or else the statement reading the first bucket
+func (o *bucketsOp) GenerateStatement() Statement {
+	return o.firstOp().GenerateStatement()
+}
+
+func (o *bucketsOp) QueryExecutor() QueryExecutor {
+	return o.firstOp().QueryExecutor()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"sort"
+	"text/template"
+)
+
+const gocassaImport = "github.com/rkilburn/gocassa"
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by gocassa-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import ({{range .Imports}}
+	{{.}}{{end}}
+)
+
+// GocassaTables returns every table generated by gocassa-gen in this package,
+// for example to create them or to generate their schema.
+func GocassaTables(ks gocassa.KeySpace) []gocassa.TableChanger {
+	return []gocassa.TableChanger{ {{- range .Tables}}
+		New{{.TypeName}}(ks),{{end}}
+	}
+}
+{{range .Tables}}{{if eq .Recipe "map"}}{{template "map" .}}{{else}}{{template "multimap" .}}{{end}}{{end}}`))
+
+func init() {
+	template.Must(fileTemplate.New("map").Parse(`
+// {{.TypeName}} is a MapTable of {{.RowType}} rows keyed by {{.Id.Name}}.
+type {{.TypeName}} = gocassa.TypedMapTable[{{.RowType}}, {{.Id.Type}}]
+
+// New{{.TypeName}} returns the {{.TypeName}} of the given keyspace.
+func New{{.TypeName}}(ks gocassa.KeySpace) *{{.TypeName}} {
+	return gocassa.NewMapTable[{{.RowType}}, {{.Id.Type}}](ks, {{printf "%q" .Name}}, {{printf "%q" .Id.Name}})
+}
+`))
+	template.Must(fileTemplate.New("multimap").Parse(`
+// {{.TypeName}} is a MultimapTable of {{.RowType}} rows partitioned by
+// {{.Partition.Name}} and keyed by {{.Id.Name}}.
+type {{.TypeName}} = gocassa.TypedMultimapTable[{{.RowType}}, {{.Partition.Type}}, {{.Id.Type}}]
+
+// New{{.TypeName}} returns the {{.TypeName}} of the given keyspace.
+func New{{.TypeName}}(ks gocassa.KeySpace) *{{.TypeName}} {
+	return gocassa.NewMultimapTable[{{.RowType}}, {{.Partition.Type}}, {{.Id.Type}}](ks, {{printf "%q" .Name}}, {{printf "%q" .Partition.Name}}, {{printf "%q" .Id.Name}})
+}
+`))
+}
+
+type templateField struct {
+	Name string
+	Type string
+}
+
+type templateTable struct {
+	Recipe    string
+	TypeName  string
+	RowType   string
+	Name      string
+	Partition templateField
+	Id        templateField
+}
+
+// generate returns the formatted source of the generated file
+func generate(pkg pkgInfo) ([]byte, error) {
+	imports := []string{`"` + gocassaImport + `"`}
+	for name, path := range pkg.imports {
+		if importName(path) == name {
+			imports = append(imports, `"`+path+`"`)
+		} else {
+			imports = append(imports, name+` "`+path+`"`)
+		}
+	}
+	sort.Strings(imports)
+
+	tables := make([]templateTable, len(pkg.tables))
+	for i, t := range pkg.tables {
+		tables[i] = templateTable{
+			Recipe:    t.recipe,
+			TypeName:  t.typeName,
+			RowType:   t.rowType,
+			Name:      t.name,
+			Partition: templateField{t.partition.name, t.partition.typ},
+			Id:        templateField{t.id.name, t.id.typ},
+		}
+	}
+
+	buf := bytes.Buffer{}
+	err := fileTemplate.Execute(&buf, map[string]interface{}{
+		"Package": pkg.name,
+		"Imports": imports,
+		"Tables":  tables,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,82 @@
+// Command gocassa-gen generates strongly typed table accessors for structs
+// annotated with gocassa directives, and optionally the CQL schema of those
+// tables.
+//
+// A struct is annotated with one directive per table in its doc comment:
+//
+//	//gocassa:map name=sale id=Id
+//	//gocassa:multimap name=sale partition=SellerId id=Id type=SalesBySeller
+//	type Sale struct {
+//		Id       string
+//		SellerId string
+//		Price    int
+//	}
+//
+// The "name" option is the table name prefix passed to the KeySpace, "id" and
+// "partition" are the (cql) names of the key fields and the optional "type"
+// option overrides the name of the generated type, which defaults to the
+// struct name followed by the recipe, for example SaleMapTable. The
+// generated types are aliases of gocassa.TypedMapTable and
+// gocassa.TypedMultimapTable, which wrap the regular recipes, so they work
+// with any KeySpace, including the mock one.
+//
+// Usage:
+//
+//	//go:generate gocassa-gen -schema schema.cql -keyspace shop
+//
+// The schema is the script of gocassa-schema for the GocassaTables function,
+// which creates the keyspace as well. Generating it compiles a small program
+// which imports the package, so this is only supported for packages other
+// than main.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package containing the annotated structs")
+	out := flag.String("out", "gocassa_gen.go", "name of the generated Go file, relative to dir")
+	schema := flag.String("schema", "", "path of the CQL schema file to generate, if any")
+	keySpace := flag.String("keyspace", "", "keyspace name used in the CQL schema")
+	flag.Parse()
+
+	if err := run(*dir, *out, *schema, *keySpace); err != nil {
+		fmt.Fprintf(os.Stderr, "gocassa-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, out, schema, keySpace string) error {
+	if schema != "" && keySpace == "" {
+		return fmt.Errorf("-keyspace is required when generating a schema")
+	}
+
+	pkg, err := parsePackage(dir, out)
+	if err != nil {
+		return err
+	}
+	if len(pkg.tables) == 0 {
+		return fmt.Errorf("no gocassa directives found in %v", dir)
+	}
+
+	src, err := generate(pkg)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, out), src, 0644); err != nil {
+		return err
+	}
+
+	if schema == "" {
+		return nil
+	}
+	cql, err := generateSchema(dir, pkg, keySpace)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(schema, cql, 0644)
+}
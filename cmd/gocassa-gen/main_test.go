@@ -0,0 +1,58 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("testdata", "shop")
+	expected, err := os.ReadFile(filepath.Join(dir, "gocassa_gen.go"))
+	require.NoError(t, err)
+
+	// The schema is generated by compiling the package, so this also checks
+	// that the generated code compiles
+	schema := filepath.Join(t.TempDir(), "shop.cql")
+	require.NoError(t, run(dir, "gocassa_gen.go", schema, "shop"))
+
+	actual, err := os.ReadFile(filepath.Join(dir, "gocassa_gen.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+
+	cql, err := os.ReadFile(schema)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(cql), "CREATE KEYSPACE IF NOT EXISTS shop "))
+	assert.Contains(t, string(cql), "CREATE TABLE IF NOT EXISTS shop.sale_map_id (")
+	assert.Contains(t, string(cql), "    PRIMARY KEY ((sellerid), id)\n")
+	assert.Equal(t, 2, strings.Count(string(cql), "CREATE TABLE"))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		"//gocassa:map name=sale\ntype Sale struct{ Id string }",
+		"//gocassa:map name=sale id=Missing\ntype Sale struct{ Id string }",
+		"//gocassa:map name=sale id=Id\ntype Sale struct{ Id string `cql:\"id\"` }",
+		"//gocassa:hash name=sale id=Id\ntype Sale struct{ Id string }",
+		"//gocassa:multimap name=sale id=Id\ntype Sale struct{ Id string }",
+		"//gocassa:map name=sale id=Id\ntype Sale string",
+		"//gocassa:map name=sale id=Id\ntype Sale struct{ Id uuid.UUID }",
+	} {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "sale.go", "package shop\n"+src, parser.ParseComments)
+		require.NoError(t, err)
+		assert.Error(t, parseFile(fset, file, &pkgInfo{imports: map[string]string{}}), src)
+	}
+}
+
+func TestImportName(t *testing.T) {
+	assert.Equal(t, "gocql", importName("github.com/gocql/gocql"))
+	assert.Equal(t, "inf", importName("gopkg.in/inf.v0"))
+	assert.Equal(t, "redis", importName("github.com/go-redis/redis/v8"))
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const directivePrefix = "//gocassa:"
+
+// recipe kinds supported by the generator
+const (
+	recipeMap      = "map"
+	recipeMultimap = "multimap"
+)
+
+type pkgInfo struct {
+	name    string
+	tables  []tableInfo
+	imports map[string]string // package name to import path, for key types
+}
+
+type tableInfo struct {
+	recipe    string
+	typeName  string // name of the generated type
+	rowType   string // name of the annotated struct
+	name      string // table name prefix
+	partition field  // only set for multimaps
+	id        field
+}
+
+type field struct {
+	name string // cql name of the field
+	typ  string // Go type of the field
+}
+
+// parsePackage finds the gocassa directives in the package in dir, ignoring
+// test files and the previously generated file
+func parsePackage(dir, generatedFile string) (pkgInfo, error) {
+	fset := token.NewFileSet()
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != generatedFile
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, parser.ParseComments)
+	if err != nil {
+		return pkgInfo{}, err
+	}
+	if len(pkgs) != 1 {
+		return pkgInfo{}, fmt.Errorf("expected a single package in %v, found %v", dir, len(pkgs))
+	}
+
+	info := pkgInfo{imports: map[string]string{}}
+	for name, pkg := range pkgs {
+		info.name = name
+		fileNames := make([]string, 0, len(pkg.Files))
+		for fileName := range pkg.Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+		for _, fileName := range fileNames {
+			if err := parseFile(fset, pkg.Files[fileName], &info); err != nil {
+				return pkgInfo{}, err
+			}
+		}
+	}
+	return info, nil
+}
+
+func parseFile(fset *token.FileSet, file *ast.File, info *pkgInfo) error {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			directives := parseDirectives(doc)
+			if len(directives) == 0 {
+				continue
+			}
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return fmt.Errorf("%v: gocassa directives can only annotate structs", fset.Position(ts.Pos()))
+			}
+			for _, d := range directives {
+				table, err := newTableInfo(ts.Name.Name, st, d)
+				if err != nil {
+					return fmt.Errorf("%v: %v", fset.Position(ts.Pos()), err)
+				}
+				for _, f := range []field{table.partition, table.id} {
+					if err := addImports(f.typ, file, info.imports); err != nil {
+						return fmt.Errorf("%v: %v", fset.Position(ts.Pos()), err)
+					}
+				}
+				info.tables = append(info.tables, table)
+			}
+		}
+	}
+	return nil
+}
+
+type directive struct {
+	recipe  string
+	options map[string]string
+}
+
+func parseDirectives(doc *ast.CommentGroup) []directive {
+	if doc == nil {
+		return nil
+	}
+	var result []directive
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
+		parts := strings.Fields(strings.TrimPrefix(c.Text, directivePrefix))
+		if len(parts) == 0 {
+			continue
+		}
+		d := directive{recipe: parts[0], options: map[string]string{}}
+		for _, part := range parts[1:] {
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) == 2 {
+				d.options[kv[0]] = kv[1]
+			} else {
+				d.options[kv[0]] = ""
+			}
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+func newTableInfo(rowType string, st *ast.StructType, d directive) (tableInfo, error) {
+	table := tableInfo{
+		recipe:  d.recipe,
+		rowType: rowType,
+		name:    d.options["name"],
+	}
+
+	var required []string
+	switch d.recipe {
+	case recipeMap:
+		required = []string{"name", "id"}
+		table.typeName = rowType + "MapTable"
+	case recipeMultimap:
+		required = []string{"name", "partition", "id"}
+		table.typeName = rowType + "MultimapTable"
+	default:
+		return table, fmt.Errorf("unknown recipe %q, expected %q or %q", d.recipe, recipeMap, recipeMultimap)
+	}
+	for _, opt := range required {
+		if d.options[opt] == "" {
+			return table, fmt.Errorf("gocassa:%v directive requires the %v option", d.recipe, opt)
+		}
+	}
+	if typeName := d.options["type"]; typeName != "" {
+		table.typeName = typeName
+	}
+
+	var err error
+	if table.id, err = findField(st, d.options["id"]); err != nil {
+		return table, err
+	}
+	if d.recipe == recipeMultimap {
+		if table.partition, err = findField(st, d.options["partition"]); err != nil {
+			return table, err
+		}
+	}
+	return table, nil
+}
+
+// findField finds the struct field stored in the given column, matching the
+// naming rules of the reflect package
+func findField(st *ast.StructType, name string) (field, error) {
+	for _, f := range st.Fields.List {
+		tagName := ""
+		if f.Tag != nil {
+			tag, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return field{}, err
+			}
+			tagName = strings.SplitN(reflect.StructTag(tag).Get("cql"), ",", 2)[0]
+		}
+		for _, ident := range f.Names {
+			if !ident.IsExported() || tagName == "-" {
+				continue
+			}
+			fieldName := ident.Name
+			if tagName != "" {
+				fieldName = tagName
+			}
+			if fieldName == name {
+				return field{name: name, typ: types.ExprString(f.Type)}, nil
+			}
+		}
+	}
+	return field{}, fmt.Errorf("no field stored as %q", name)
+}
+
+// addImports records the imports needed to refer to a key type from the
+// generated file
+func addImports(typ string, file *ast.File, imports map[string]string) error {
+	if typ == "" {
+		return nil
+	}
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return err
+	}
+	var missing error
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkgName := sel.X.(*ast.Ident).Name
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := importName(path)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name == pkgName {
+				imports[pkgName] = path
+				return false
+			}
+		}
+		missing = fmt.Errorf("can't find the import of package %v used by key type %v", pkgName, typ)
+		return false
+	})
+	return missing
+}
+
+// importName guesses the name of the package with the given import path,
+// ignoring major version suffixes such as in "gopkg.in/inf.v0" or
+// "example.com/pkg/v2"
+func importName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && isMajorVersion(name) {
+		name = parts[len(parts)-2]
+	}
+	if i := strings.LastIndex(name, "."); i > 0 && isMajorVersion(name[i+1:]) {
+		name = name[:i]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(s[1:])
+	return err == nil
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rkilburn/gocassa/internal/gorun"
+)
+
+// generateSchema returns the CQL creating the keyspace and the generated
+// tables. The statements are produced by the gocassa package itself, by
+// running a small program which imports the package with the generated tables.
+func generateSchema(dir string, pkg pkgInfo, keySpace string) ([]byte, error) {
+	if pkg.name == "main" {
+		return nil, fmt.Errorf("can't generate a schema for tables in package main")
+	}
+	return gorun.SchemaScript(dir, "GocassaTables", keySpace, "")
+}
@@ -0,0 +1,34 @@
+// Code generated by gocassa-gen. DO NOT EDIT.
+
+package shop
+
+import (
+	"github.com/gocql/gocql"
+	"github.com/rkilburn/gocassa"
+)
+
+// GocassaTables returns every table generated by gocassa-gen in this package,
+// for example to create them or to generate their schema.
+func GocassaTables(ks gocassa.KeySpace) []gocassa.TableChanger {
+	return []gocassa.TableChanger{
+		NewSaleMapTable(ks),
+		NewSalesBySeller(ks),
+	}
+}
+
+// SaleMapTable is a MapTable of Sale rows keyed by id.
+type SaleMapTable = gocassa.TypedMapTable[Sale, gocql.UUID]
+
+// NewSaleMapTable returns the SaleMapTable of the given keyspace.
+func NewSaleMapTable(ks gocassa.KeySpace) *SaleMapTable {
+	return gocassa.NewMapTable[Sale, gocql.UUID](ks, "sale", "id")
+}
+
+// SalesBySeller is a MultimapTable of Sale rows partitioned by
+// SellerId and keyed by id.
+type SalesBySeller = gocassa.TypedMultimapTable[Sale, string, gocql.UUID]
+
+// NewSalesBySeller returns the SalesBySeller of the given keyspace.
+func NewSalesBySeller(ks gocassa.KeySpace) *SalesBySeller {
+	return gocassa.NewMultimapTable[Sale, string, gocql.UUID](ks, "sale", "SellerId", "id")
+}
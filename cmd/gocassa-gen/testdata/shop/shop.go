@@ -0,0 +1,23 @@
+// Package shop is used to test gocassa-gen
+package shop
+
+import (
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+//gocassa:map name=sale id=id
+//gocassa:multimap name=sale partition=SellerId id=id type=SalesBySeller
+type Sale struct {
+	Id       gocql.UUID `cql:"id"`
+	SellerId string
+	Price    int
+	Created  time.Time
+	Tags     []string `cql:"tags,set"`
+}
+
+// Not annotated, so no table is generated
+type Seller struct {
+	Id string
+}
@@ -0,0 +1,57 @@
+// Command gocassa-schema prints the CQL script creating the keyspace, types
+// and tables used by an application, so the schema can be reviewed and
+// applied before deploying instead of being created by the application.
+//
+// The tables are found by calling a function of the application which creates
+// them through the given KeySpace, every table created this way is recorded
+// (see gocassa.KeySpace.RegisteredTables). The function must be exported from
+// a package other than main and take a gocassa.KeySpace as its only argument,
+// for example:
+//
+//	func Tables(ks gocassa.KeySpace) {
+//		ks.MapTable("sale", "Id", Sale{})
+//		ks.TimeSeriesTable("sale", "Created", "Id", 24*time.Hour, Sale{})
+//	}
+//
+// The GocassaTables function generated by gocassa-gen can be used as well.
+//
+// Usage:
+//
+//	gocassa-schema -dir ./store -func Tables -keyspace shop > schema.cql
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rkilburn/gocassa/internal/gorun"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package declaring the tables")
+	fn := flag.String("func", "GocassaTables", "function creating the tables from a gocassa.KeySpace")
+	keySpace := flag.String("keyspace", "", "name of the keyspace")
+	replication := flag.String("replication", "", "replication options of the keyspace, defaults to SimpleStrategy with a replication factor of 1")
+	out := flag.String("out", "", "file to write the script to instead of stdout")
+	flag.Parse()
+
+	script, err := schemaScript(*dir, *fn, *keySpace, *replication)
+	if err == nil && *out != "" {
+		err = os.WriteFile(*out, script, 0644)
+	} else if err == nil {
+		_, err = os.Stdout.Write(script)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gocassa-schema: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func schemaScript(dir, fn, keySpace, replication string) ([]byte, error) {
+	if keySpace == "" {
+		return nil, fmt.Errorf("-keyspace is required")
+	}
+
+	return gorun.SchemaScript(dir, fn, keySpace, replication)
+}
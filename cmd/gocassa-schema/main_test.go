@@ -0,0 +1,22 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaScript(t *testing.T) {
+	script, err := schemaScript("../gocassa-gen/testdata/shop", "GocassaTables", "shop", "")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(script), "CREATE KEYSPACE IF NOT EXISTS shop WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1};\n\n"))
+	assert.Contains(t, string(script), "CREATE TABLE IF NOT EXISTS shop.sale_map_id (")
+	assert.Contains(t, string(script), "CREATE TABLE IF NOT EXISTS shop.sale_multimap_SellerId_id (")
+
+	_, err = schemaScript("../gocassa-gen/testdata/shop", "Missing", "shop", "")
+	assert.Error(t, err)
+	_, err = schemaScript("../gocassa-gen/testdata/shop", "GocassaTables", "", "")
+	assert.Error(t, err)
+}
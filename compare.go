@@ -17,8 +17,13 @@
 package gocassa
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"reflect"
+
+	"github.com/gocql/gocql"
+	"gopkg.in/inf.v0"
 )
 
 func builtinLessThan(k1, k2 interface{}) (bool, error) {
@@ -69,6 +74,15 @@ func builtinLessThan(k1, k2 interface{}) (bool, error) {
 
 	case uintptr:
 		return k1 < k2.(uintptr), nil
+
+	case *big.Int:
+		return k1.Cmp(k2.(*big.Int)) < 0, nil
+
+	case *inf.Dec:
+		return k1.Cmp(k2.(*inf.Dec)) < 0, nil
+
+	case gocql.UUID:
+		return compareUUIDs(k1, k2.(gocql.UUID)) < 0, nil
 	}
 
 	return false, fmt.Errorf("skiplist/BuiltinLessThan: unsupported types for k1.(%s) and k2.(%s)",
@@ -123,8 +137,55 @@ func builtinGreaterThan(k1, k2 interface{}) (bool, error) {
 
 	case uintptr:
 		return k1 > k2.(uintptr), nil
+
+	case *big.Int:
+		return k1.Cmp(k2.(*big.Int)) > 0, nil
+
+	case *inf.Dec:
+		return k1.Cmp(k2.(*inf.Dec)) > 0, nil
+
+	case gocql.UUID:
+		return compareUUIDs(k1, k2.(gocql.UUID)) > 0, nil
 	}
 
 	return false, fmt.Errorf("skiplist/BuiltinGreaterThan: unsupported types for k1.(%s) and k2.(%s)",
 		reflect.TypeOf(k1).Name(), reflect.TypeOf(k2).Name())
 }
+
+// compareUUIDs compares UUIDs the way Cassandra orders timeuuid columns:
+// time UUIDs by their timestamp first, and then by the bytes of their clock
+// sequence and node as signed bytes. Other UUIDs are compared byte-wise.
+func compareUUIDs(a, b gocql.UUID) int {
+	if a.Version() != 1 || b.Version() != 1 {
+		return bytes.Compare(a[:], b[:])
+	}
+	if ta, tb := a.Timestamp(), b.Timestamp(); ta != tb {
+		if ta < tb {
+			return -1
+		}
+		return 1
+	}
+	for i := 8; i < 16; i++ {
+		if ca, cb := int8(a[i]), int8(b[i]); ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// builtinEquals compares two values for equality, comparing the numeric value
+// of arbitrary precision numbers rather than their pointers
+func builtinEquals(k1, k2 interface{}) bool {
+	switch k1 := k1.(type) {
+	case *big.Int:
+		k2, ok := k2.(*big.Int)
+		return ok && k1.Cmp(k2) == 0
+	case *inf.Dec:
+		k2, ok := k2.(*inf.Dec)
+		return ok && k1.Cmp(k2) == 0
+	}
+	return k1 == k2
+}
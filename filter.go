@@ -1,12 +1,14 @@
 package gocassa
 
+import "encoding/json"
+
 type filter struct {
 	t  t
 	rs []Relation
 }
 
 func (f filter) Table() Table {
-	return f.t
+	return &f.t
 }
 
 func (f filter) Relations() []Relation {
@@ -21,6 +23,12 @@ func (f filter) Delete() Op {
 	return newWriteOp(f.t.keySpace.qe, f, deleteOpType, nil)
 }
 
+func (f filter) DeleteColumns(columns ...DeleteColumn) Op {
+	op := newWriteOp(f.t.keySpace.qe, f, deleteOpType, nil)
+	op.columns = columns
+	return op
+}
+
 //
 // Reads
 //
@@ -40,3 +48,26 @@ func (f filter) ReadOne(pointer interface{}) Op {
 		opType: singleReadOpType,
 		result: pointer}
 }
+
+func (f filter) ReadJSON(out *[]json.RawMessage) Op {
+	return &singleOp{
+		qe:       f.t.keySpace.qe,
+		f:        f,
+		opType:   readOpType,
+		result:   out,
+		readJSON: true}
+}
+
+func (f filter) Count(count *int64) Op {
+	return f.Aggregate(AggregateCount, "*", count)
+}
+
+func (f filter) Aggregate(fn AggregateFunc, field string, result interface{}) Op {
+	return &singleOp{
+		qe:             f.t.keySpace.qe,
+		f:              f,
+		opType:         readOpType,
+		result:         result,
+		aggregate:      fn,
+		aggregateField: field}
+}
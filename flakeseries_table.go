@@ -1,14 +1,9 @@
 package gocassa
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"strings"
 	"time"
-
-	"github.com/mattheath/base62"
-	"github.com/mattheath/kala/bigflake"
-	"github.com/mattheath/kala/util"
 )
 
 // we have to put the timestamp from the flake ID into a field so that we can
@@ -30,6 +25,9 @@ func (o *flakeSeriesT) CreateStatement() (Statement, error) { return o.Table().C
 func (o *flakeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *flakeSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
 
 func (o *flakeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -41,23 +39,23 @@ func (o *flakeSeriesT) Set(v interface{}) Op {
 		panic(fmt.Sprintf("Id field (%s) is not present or is not a string", o.idField))
 	}
 
-	timestamp, err := flakeToTime(id)
+	timestamp, err := o.idCodec().Time(id)
 	if err != nil {
 		return errOp{err: err}
 	}
 
 	m[flakeTimestampFieldName] = timestamp
-	m[bucketFieldName] = bucket(timestamp, o.bucketSize)
+	m[bucketFieldName] = o.bucketing().Bucket(timestamp)
 
 	return o.Table().Set(m)
 }
 
 func (o *flakeSeriesT) Update(id string, m map[string]interface{}) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := o.idCodec().Time(id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timestamp)
 
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
@@ -66,12 +64,16 @@ func (o *flakeSeriesT) Update(id string, m map[string]interface{}) Op {
 		Update(m)
 }
 
+func (o *flakeSeriesT) UpdateStatic(timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(timeStamp).Filter().Update(m)
+}
+
 func (o *flakeSeriesT) Delete(id string) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := o.idCodec().Time(id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timestamp)
 
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
@@ -81,11 +83,11 @@ func (o *flakeSeriesT) Delete(id string) Op {
 }
 
 func (o *flakeSeriesT) Read(id string, pointer interface{}) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := o.idCodec().Time(id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timestamp)
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
 			Eq(flakeTimestampFieldName, timestamp),
@@ -94,70 +96,62 @@ func (o *flakeSeriesT) Read(id string, pointer interface{}) Op {
 }
 
 func (o *flakeSeriesT) List(startTime, endTime time.Time, pointerToASlice interface{}) Op {
-	buckets := []interface{}{}
-	for bucket := o.Buckets(startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
-		buckets = append(buckets, bucket.Bucket())
-	}
-	return o.Table().
-		Where(In(bucketFieldName, buckets...),
-			GTE(flakeTimestampFieldName, startTime),
-			LT(flakeTimestampFieldName, endTime)).
-		Read(pointerToASlice)
+	return newBucketsOp(o.Buckets(startTime), o.Buckets(endTime), endTime,
+		[]Relation{GTE(flakeTimestampFieldName, startTime), LT(flakeTimestampFieldName, endTime)},
+		pointerToASlice)
+}
+
+func (o *flakeSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
+}
+
+func (o *flakeSeriesT) idCodec() IDCodec {
+	return tableIDCodec(o.Table())
 }
 
 func (o *flakeSeriesT) Buckets(start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		bucketing: o.bucketing(),
 		field:     bucketFieldName,
 		invariant: o.Table().Where()}
 }
 
+func (o *flakeSeriesT) Retention(ctx context.Context, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(start), cutoff)
+}
+
 func (o *flakeSeriesT) ListSince(id string, window time.Duration, pointerToASlice interface{}) Op {
-	startTime, err := flakeToTime(id)
+	codec, err := sinceCodec(o.Table())
 	if err != nil {
 		return errOp{err: err}
 	}
-
-	var endTime time.Time
-	if window == 0 {
-		// no window set - so go up until 5 mins in the future
-		endTime = time.Now().Add(5 * time.Minute)
-	} else {
-		endTime = startTime.Add(window)
+	startTime, err := codec.Time(id)
+	if err != nil {
+		return errOp{err: err}
 	}
 
-	buckets := []interface{}{}
-	for bucket := o.Buckets(startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
-		buckets = append(buckets, bucket.Bucket())
-	}
+	endTime := sinceEndTime(startTime, window)
 
-	return o.Table().
-		Where(In(bucketFieldName, buckets),
-			GTE(flakeTimestampFieldName, startTime),
-			LT(flakeTimestampFieldName, endTime),
-			GT(o.idField, id)).
-		Read(pointerToASlice)
+	return newBucketsOp(o.Buckets(startTime), o.Buckets(endTime), endTime,
+		[]Relation{GTE(flakeTimestampFieldName, startTime), LT(flakeTimestampFieldName, endTime), GT(o.idField, id)},
+		pointerToASlice)
 }
 
 func (o *flakeSeriesT) WithOptions(opt Options) FlakeSeriesTable {
-	return &flakeSeriesT{
+	result := &flakeSeriesT{
 		t:          o.Table().WithOptions(opt),
 		idField:    o.idField,
 		bucketSize: o.bucketSize}
+	reregister(o, result)
+	return result
 }
 
-func flakeToTime(id string) (time.Time, error) {
-	parts := strings.Split(id, "_")
-
-	if len(parts) < 2 {
-		return time.Time{}, errors.New("Invalid flake id")
+// sinceEndTime returns the end of the window of a ListSince starting at the
+// given time. Without a window it lists up until 5 minutes in the future.
+func sinceEndTime(startTime time.Time, window time.Duration) time.Time {
+	if window == 0 {
+		return time.Now().Add(5 * time.Minute)
 	}
-
-	intId := base62.DecodeToBigInt(parts[len(parts)-1])
-
-	msTime, _, _ := bigflake.ParseId(intId)
-	timestamp := util.MsInt64ToTime(msTime)
-
-	return timestamp, nil
+	return startTime.Add(window)
 }
@@ -3,11 +3,16 @@ package gocassa
 import (
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
 	"reflect"
 	"strings"
 	"time"
 
 	"github.com/gocql/gocql"
+	"gopkg.in/inf.v0"
+
+	r "github.com/rkilburn/gocassa/reflect"
 )
 
 // CREATE TABLE users (
@@ -28,23 +33,86 @@ import (
 // );
 //
 
-func createTableIfNotExist(keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string) (Statement, error) {
-	return createTableStmt("CREATE TABLE IF NOT EXISTS", keySpace, cf, partitionKeys, colKeys, fields, values, order, compoundKey, compact, compressor)
+// CreateIfNotExistStatements returns the statements creating the given tables
+// and the user-defined types they use, if they don't exist yet. Types shared
+// by several tables are only created once, before the first table using them.
+func CreateIfNotExistStatements(tables ...TableChanger) ([]Statement, error) {
+	stmts := []Statement{}
+	seenTypes := map[string]bool{}
+	for _, table := range tables {
+		typeStmts, err := table.CreateTypeStatements()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", table.Name(), err)
+		}
+		for _, stmt := range typeStmts {
+			if !seenTypes[stmt.Query()] {
+				seenTypes[stmt.Query()] = true
+				stmts = append(stmts, stmt)
+			}
+		}
+
+		stmt, err := table.CreateIfNotExistStatement()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", table.Name(), err)
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts, nil
 }
 
-func createTable(keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string) (Statement, error) {
-	return createTableStmt("CREATE TABLE", keySpace, cf, partitionKeys, colKeys, fields, values, order, compoundKey, compact, compressor)
+// SchemaScript returns a CQL script creating the keyspace with the given
+// replication options, followed by the types and tables registered on it, all
+// only if they don't exist yet. An empty replication defaults to
+// SimpleStrategy with a replication factor of 1.
+func SchemaScript(ks KeySpace, replication string) (string, error) {
+	if replication == "" {
+		replication = "{'class': 'SimpleStrategy', 'replication_factor': 1}"
+	}
+	stmts, err := CreateIfNotExistStatements(ks.RegisteredTables()...)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{fmt.Sprintf("CREATE KEYSPACE IF NOT EXISTS %v WITH replication = %v;", ks.Name(), replication)}
+	for _, stmt := range stmts {
+		lines = append(lines, stmt.Query())
+	}
+	return strings.Join(lines, "\n\n") + "\n", nil
 }
 
-func createTableStmt(createStmt, keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string) (Statement, error) {
-	firstLine := fmt.Sprintf("%s %v.%v (", createStmt, keySpace, cf)
+func createTypesIfNotExist(keySpace string, fields []string, values []interface{}, fieldOptions map[string]columnOptions) ([]Statement, error) {
+	return createTypeStmts("CREATE TYPE IF NOT EXISTS", keySpace, fields, values, fieldOptions)
+}
+
+func createTableIfNotExist(keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, fieldOptions map[string]columnOptions, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string, caseSensitive bool) (Statement, error) {
+	return createTableStmt("CREATE TABLE IF NOT EXISTS", keySpace, cf, partitionKeys, colKeys, fields, values, fieldOptions, order, compoundKey, compact, compressor, caseSensitive)
+}
+
+func createTable(keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, fieldOptions map[string]columnOptions, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string, caseSensitive bool) (Statement, error) {
+	return createTableStmt("CREATE TABLE", keySpace, cf, partitionKeys, colKeys, fields, values, fieldOptions, order, compoundKey, compact, compressor, caseSensitive)
+}
+
+func createTableStmt(createStmt, keySpace, cf string, partitionKeys, colKeys []string, fields []string, values []interface{}, fieldOptions map[string]columnOptions, order []ClusteringOrderColumn, compoundKey, compact bool, compressor string, caseSensitive bool) (Statement, error) {
+	if err := validateTableName(cf); err != nil {
+		return nil, err
+	}
+	firstLine := fmt.Sprintf("%s %v (", createStmt, tableIdentifier(keySpace, cf, caseSensitive))
 	fieldLines := []string{}
 	for i, _ := range fields {
-		typeStr, err := stringTypeOf(values[i])
+		typeStr, err := cqlTypeOf(reflect.TypeOf(values[i]), fieldOptions[fields[i]])
 		if err != nil {
 			return nil, err
 		}
-		l := "    " + strings.ToLower(fields[i]) + " " + typeStr
+		l := "    " + columnName(fields[i], caseSensitive) + " " + typeStr
+		if fieldOptions[fields[i]].static {
+			if len(colKeys) == 0 {
+				return nil, fmt.Errorf("Static column %v requires clustering columns", fields[i])
+			}
+			if isKeyColumn(fields[i], partitionKeys, colKeys) {
+				return nil, fmt.Errorf("Primary key column %v can't be static", fields[i])
+			}
+			l += " STATIC"
+		}
 		fieldLines = append(fieldLines, l)
 	}
 	//key generation
@@ -57,7 +125,7 @@ func createTableStmt(createStmt, keySpace, cf string, partitionKeys, colKeys []s
 		str = "    PRIMARY KEY ((%v %v))"
 	}
 
-	fieldLines = append(fieldLines, fmt.Sprintf(str, j(partitionKeys), j(colKeys)))
+	fieldLines = append(fieldLines, fmt.Sprintf(str, j(partitionKeys, caseSensitive), j(colKeys, caseSensitive)))
 
 	lines := []string{
 		firstLine,
@@ -68,7 +136,7 @@ func createTableStmt(createStmt, keySpace, cf string, partitionKeys, colKeys []s
 	if len(order) > 0 {
 		orderStrs := make([]string, len(order))
 		for i, o := range order {
-			orderStrs[i] = fmt.Sprintf("%v %v", o.Column, o.Direction.String())
+			orderStrs[i] = fmt.Sprintf("%v %v", columnName(o.Column, caseSensitive), o.Direction.String())
 		}
 		orderLine := fmt.Sprintf("WITH CLUSTERING ORDER BY (%v)", strings.Join(orderStrs, ", "))
 		lines = append(lines, orderLine)
@@ -97,12 +165,17 @@ func createTableStmt(createStmt, keySpace, cf string, partitionKeys, colKeys []s
 	return cqlStatement{query: qry}, nil
 }
 
-func j(s []string) string {
-	s1 := []string{}
-	for _, v := range s {
-		s1 = append(s1, strings.ToLower(v))
+func isKeyColumn(field string, partitionKeys, colKeys []string) bool {
+	for _, k := range append(append([]string{}, partitionKeys...), colKeys...) {
+		if strings.EqualFold(k, field) {
+			return true
+		}
 	}
-	return strings.Join(s1, ", ")
+	return false
+}
+
+func j(s []string, caseSensitive bool) string {
+	return strings.Join(columnNames(s, caseSensitive), ", ")
 }
 
 func cassaType(i interface{}) gocql.Type {
@@ -111,8 +184,16 @@ func cassaType(i interface{}) gocql.Type {
 		return gocql.TypeInt
 	case int64:
 		return gocql.TypeBigInt
-	case int8, int16, uint, uint8, uint16, uint32, uint64:
+	case int16:
+		return gocql.TypeSmallInt
+	case int8:
+		return gocql.TypeTinyInt
+	case uint, uint8, uint16, uint32, uint64, *big.Int:
+		// CQL has no unsigned integer types, so these are stored as varint
+		// to make sure every value fits
 		return gocql.TypeVarint
+	case *inf.Dec:
+		return gocql.TypeDecimal
 	case string:
 		return gocql.TypeVarchar
 	case float32:
@@ -123,8 +204,12 @@ func cassaType(i interface{}) gocql.Type {
 		return gocql.TypeBoolean
 	case time.Time:
 		return gocql.TypeTimestamp
+	case gocql.Duration:
+		return gocql.TypeDuration
 	case gocql.UUID:
 		return gocql.TypeUUID
+	case net.IP:
+		return gocql.TypeInet
 	case []byte:
 		return gocql.TypeBlob
 	case Counter:
@@ -134,8 +219,12 @@ func cassaType(i interface{}) gocql.Type {
 	// Fallback to using reflection if type not recognised
 	typ := reflect.TypeOf(i)
 	switch typ.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+	case reflect.Int, reflect.Int32:
 		return gocql.TypeInt
+	case reflect.Int16:
+		return gocql.TypeSmallInt
+	case reflect.Int8:
+		return gocql.TypeTinyInt
 	case reflect.Int64:
 		return gocql.TypeBigInt
 	case reflect.String:
@@ -151,44 +240,265 @@ func cassaType(i interface{}) gocql.Type {
 	return gocql.TypeCustom
 }
 
+// columnOptions holds the schema options declared in the struct tag of a field
+type columnOptions struct {
+	set    bool // store a slice as a set<> rather than a list<>
+	tuple  bool // store a struct as a tuple<> rather than a user-defined type
+	frozen bool // store a collection as a single frozen value
+	static bool // share the column between all rows of a partition
+
+	// cqlType overrides the CQL type of the column, for example "date" to
+	// store a time.Time without its time of day
+	cqlType string
+}
+
+func columnOptionsOfField(f r.Field) columnOptions {
+	cqlType, _ := f.Option("type")
+	return columnOptions{
+		set:     f.HasOption("set"),
+		tuple:   f.HasOption("tuple"),
+		frozen:  f.HasOption("frozen"),
+		static:  f.HasOption("static"),
+		cqlType: strings.TrimSpace(cqlType),
+	}
+}
+
+// fieldColumnOptions returns the column options declared on the fields of
+// the given row struct, keyed by field name
+func fieldColumnOptions(entity interface{}) map[string]columnOptions {
+	result := map[string]columnOptions{}
+	typ := reflect.TypeOf(entity)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return result
+	}
+	fields, _ := r.StructFields(typ)
+	for _, f := range fields {
+		if opts := columnOptionsOfField(f); opts != (columnOptions{}) {
+			result[f.Name()] = opts
+		}
+	}
+	return result
+}
+
+// overrideColumnType returns a copy of the column options of the fields with
+// the CQL type of the given field overridden
+func overrideColumnType(fieldOptions map[string]columnOptions, field, cqlType string) map[string]columnOptions {
+	result := make(map[string]columnOptions, len(fieldOptions)+1)
+	for k, v := range fieldOptions {
+		result[k] = v
+	}
+	opts := result[field]
+	opts.cqlType = cqlType
+	result[field] = opts
+	return result
+}
+
 func stringTypeOf(i interface{}) (string, error) {
-	_, isByteSlice := i.([]byte)
-	if !isByteSlice {
+	return cqlTypeOf(reflect.TypeOf(i), columnOptions{})
+}
+
+// cqlTypeOf returns the CQL type of a column holding values of the given type
+func cqlTypeOf(typ reflect.Type, opts columnOptions) (string, error) {
+	return cqlTypeString(typ, opts, false)
+}
+
+// cqlTypeString returns the CQL type for the given Go type. Collections which
+// are nested within other collections or tuples are always frozen
+func cqlTypeString(typ reflect.Type, opts columnOptions, nested bool) (string, error) {
+	if opts.cqlType != "" && !nested {
+		return opts.cqlType, nil
+	}
+	if typ == nil {
+		return "", fmt.Errorf("Unsupported type %v", typ)
+	}
+	if isUDTType(typ) {
+		if opts.tuple {
+			return tupleTypeString(typ)
+		}
+		return udtTypeString(typ)
+	}
+	// Element types only inherit the tuple option, the rest apply to the
+	// outermost collection
+	elemOpts := columnOptions{tuple: opts.tuple}
+	if typ != byteSliceType && typ != ipType {
 		// Check if we found a higher kinded type
-		switch reflect.ValueOf(i).Kind() {
+		collection := ""
+		switch typ.Kind() {
 		case reflect.Slice:
-			elemVal := reflect.Indirect(reflect.New(reflect.TypeOf(i).Elem())).Interface()
-			ct := cassaType(elemVal)
-			if ct == gocql.TypeCustom {
-				return "", fmt.Errorf("Unsupported type %T", i)
+			elemStr, err := cqlTypeString(typ.Elem(), elemOpts, true)
+			if err != nil {
+				return "", fmt.Errorf("Unsupported type %v", typ)
 			}
-			return fmt.Sprintf("list<%v>", ct), nil
+			kind := "list"
+			if opts.set {
+				kind = "set"
+			}
+			collection = fmt.Sprintf("%v<%v>", kind, elemStr)
 		case reflect.Map:
-			keyVal := reflect.Indirect(reflect.New(reflect.TypeOf(i).Key())).Interface()
-			elemVal := reflect.Indirect(reflect.New(reflect.TypeOf(i).Elem())).Interface()
-			keyCt := cassaType(keyVal)
-			elemCt := cassaType(elemVal)
-			if keyCt == gocql.TypeCustom || elemCt == gocql.TypeCustom {
-				return "", fmt.Errorf("Unsupported map key or value type %T", i)
+			keyStr, keyErr := cqlTypeString(typ.Key(), elemOpts, true)
+			elemStr, elemErr := cqlTypeString(typ.Elem(), elemOpts, true)
+			if keyErr != nil || elemErr != nil {
+				return "", fmt.Errorf("Unsupported map key or value type %v", typ)
+			}
+			collection = fmt.Sprintf("map<%v, %v>", keyStr, elemStr)
+		}
+		if collection != "" {
+			if nested || opts.frozen {
+				return fmt.Sprintf("frozen<%v>", collection), nil
 			}
-			return fmt.Sprintf("map<%v, %v>", keyCt, elemCt), nil
+			return collection, nil
 		}
 	}
-	ct := cassaType(i)
+	ct := cassaType(reflect.Zero(typ).Interface())
 	if ct == gocql.TypeCustom {
-		return "", fmt.Errorf("Unsupported type %T", i)
+		return "", fmt.Errorf("Unsupported type %v", typ)
 	}
 	return cassaTypeToString(ct)
 }
 
+func udtTypeString(typ reflect.Type) (string, error) {
+	name := udtName(typ)
+	if name == "" {
+		return "", fmt.Errorf("Unsupported anonymous struct type %v", typ)
+	}
+	return fmt.Sprintf("frozen<%v>", identifier(name, false)), nil
+}
+
+// tupleTypeString returns the tuple type for a struct, with the elements
+// taken from the struct fields in declaration order
+func tupleTypeString(typ reflect.Type) (string, error) {
+	fields, err := r.StructFields(typ)
+	if err != nil {
+		return "", err
+	}
+	if len(fields) == 0 {
+		return "", fmt.Errorf("Unsupported empty tuple type %v", typ)
+	}
+	elems := make([]string, len(fields))
+	for i, f := range fields {
+		if elems[i], err = cqlTypeString(f.Type(), columnOptionsOfField(f), true); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("tuple<%v>", strings.Join(elems, ", ")), nil
+}
+
+// CREATE TYPE address (
+//   street varchar,
+//   city varchar
+// );
+//
+
+// createTypeStmts generates the CREATE TYPE statements needed for all the
+// user-defined types referenced by the given fields. Types which are
+// referenced by other types are created first
+func createTypeStmts(createStmt, keySpace string, fields []string, values []interface{}, fieldOptions map[string]columnOptions) ([]Statement, error) {
+	types := []reflect.Type{}
+	seen := map[string]reflect.Type{}
+	for i, v := range values {
+		var err error
+		if types, err = collectUDTs(reflect.TypeOf(v), fieldOptions[fields[i]], types, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	stmts := make([]Statement, 0, len(types))
+	for _, typ := range types {
+		structFields, err := r.StructFields(typ)
+		if err != nil {
+			return nil, err
+		}
+		fieldLines := make([]string, 0, len(structFields))
+		for _, f := range structFields {
+			typeStr, err := cqlTypeOf(f.Type(), columnOptionsOfField(f))
+			if err != nil {
+				return nil, err
+			}
+			fieldLines = append(fieldLines, "    "+columnName(f.Name(), false)+" "+typeStr)
+		}
+		lines := []string{
+			fmt.Sprintf("%s %v (", createStmt, tableIdentifier(keySpace, udtName(typ), false)),
+			strings.Join(fieldLines, ",\n"),
+			");",
+		}
+		stmts = append(stmts, cqlStatement{query: strings.Join(lines, "\n")})
+	}
+	return stmts, nil
+}
+
+// collectUDTs walks the given type depth first and appends every
+// user-defined type it finds to types, dependencies first
+func collectUDTs(typ reflect.Type, opts columnOptions, types []reflect.Type, seen map[string]reflect.Type) ([]reflect.Type, error) {
+	if typ == nil || typ == byteSliceType {
+		return types, nil
+	}
+	switch {
+	case isUDTType(typ) && opts.tuple:
+		fields, err := r.StructFields(typ)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			if types, err = collectUDTs(f.Type(), columnOptionsOfField(f), types, seen); err != nil {
+				return nil, err
+			}
+		}
+		return types, nil
+	case isUDTType(typ):
+		name := udtName(typ)
+		if name == "" {
+			return nil, fmt.Errorf("Unsupported anonymous struct type %v", typ)
+		}
+		if other, ok := seen[name]; ok {
+			if other != typ {
+				return nil, fmt.Errorf("Types %v and %v map to the same user-defined type %v", other, typ, name)
+			}
+			return types, nil
+		}
+		seen[name] = typ
+		fields, err := r.StructFields(typ)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range fields {
+			if types, err = collectUDTs(f.Type(), columnOptionsOfField(f), types, seen); err != nil {
+				return nil, err
+			}
+		}
+		return append(types, typ), nil
+	case typ.Kind() == reflect.Slice:
+		return collectUDTs(typ.Elem(), columnOptions{tuple: opts.tuple}, types, seen)
+	case typ.Kind() == reflect.Map:
+		types, err := collectUDTs(typ.Key(), columnOptions{tuple: opts.tuple}, types, seen)
+		if err != nil {
+			return nil, err
+		}
+		return collectUDTs(typ.Elem(), columnOptions{tuple: opts.tuple}, types, seen)
+	}
+	return types, nil
+}
+
 func cassaTypeToString(t gocql.Type) (string, error) {
 	switch t {
 	case gocql.TypeInt:
 		return "int", nil
 	case gocql.TypeBigInt:
 		return "bigint", nil
+	case gocql.TypeSmallInt:
+		return "smallint", nil
+	case gocql.TypeTinyInt:
+		return "tinyint", nil
 	case gocql.TypeVarint:
 		return "varint", nil
+	case gocql.TypeDecimal:
+		return "decimal", nil
+	case gocql.TypeAscii:
+		return "ascii", nil
+	case gocql.TypeText:
+		return "text", nil
 	case gocql.TypeVarchar:
 		return "varchar", nil
 	case gocql.TypeFloat:
@@ -199,8 +509,18 @@ func cassaTypeToString(t gocql.Type) (string, error) {
 		return "boolean", nil
 	case gocql.TypeTimestamp:
 		return "timestamp", nil
+	case gocql.TypeDate:
+		return "date", nil
+	case gocql.TypeTime:
+		return "time", nil
+	case gocql.TypeDuration:
+		return "duration", nil
 	case gocql.TypeUUID:
 		return "uuid", nil
+	case gocql.TypeTimeUUID:
+		return "timeuuid", nil
+	case gocql.TypeInet:
+		return "inet", nil
 	case gocql.TypeBlob:
 		return "blob", nil
 	case gocql.TypeCounter:
@@ -0,0 +1,287 @@
+package gocassa
+
+import (
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inf.v0"
+)
+
+type geoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+type postalAddress struct {
+	Street   string `cql:"street_name"`
+	City     string
+	Location geoPoint
+}
+
+type customerWithAddress struct {
+	Id        string
+	Home      postalAddress
+	Previous  []postalAddress
+	Locations map[string]geoPoint
+}
+
+func TestStringTypeOfUDT(t *testing.T) {
+	typ, err := stringTypeOf(postalAddress{})
+	assert.NoError(t, err)
+	assert.Equal(t, "frozen<postaladdress>", typ)
+
+	typ, err = stringTypeOf([]postalAddress{})
+	assert.NoError(t, err)
+	assert.Equal(t, "list<frozen<postaladdress>>", typ)
+
+	typ, err = stringTypeOf(map[string]geoPoint{})
+	assert.NoError(t, err)
+	assert.Equal(t, "map<varchar, frozen<geopoint>>", typ)
+
+	_, err = stringTypeOf(struct{ A int }{})
+	assert.Error(t, err)
+	_, err = createTypeStmts("CREATE TYPE", "ks1", []string{"A"}, []interface{}{struct{ A int }{}}, nil)
+	assert.Error(t, err)
+}
+
+type taggedCollections struct {
+	Id       string
+	Tags     []string           `cql:"tags,set"`
+	Position geoPoint           `cql:"position,tuple"`
+	Path     []geoPoint         `cql:"path,tuple"`
+	Groups   map[string][]int   `cql:"groups"`
+	Frozen   map[string]float64 `cql:"frozen,frozen"`
+}
+
+func TestCqlTypeOfCollections(t *testing.T) {
+	typ, err := cqlTypeOf(reflect.TypeOf([]string{}), columnOptions{set: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "set<varchar>", typ)
+
+	typ, err = cqlTypeOf(reflect.TypeOf(geoPoint{}), columnOptions{tuple: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "tuple<double, double>", typ)
+
+	typ, err = stringTypeOf(map[string][]int{})
+	assert.NoError(t, err)
+	assert.Equal(t, "map<varchar, frozen<list<int>>>", typ)
+
+	typ, err = stringTypeOf([][]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "list<frozen<list<varchar>>>", typ)
+
+	m, _ := toMap(taggedCollections{})
+	ti := newTableInfo("ks1", "tagged", Keys{PartitionKeys: []string{"Id"}}, taggedCollections{}, m)
+	stmts, err := createTypeStmts("CREATE TYPE", "ks1", ti.fields, ti.fieldValues, ti.fieldOptions)
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 0)
+
+	stmt, err := createTable("ks1", "tagged", ti.keys.PartitionKeys, nil, ti.fields, ti.fieldValues, ti.fieldOptions, nil, false, false, "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE ks1.tagged (\n"+
+		"    id varchar,\n"+
+		"    frozen frozen<map<varchar, double>>,\n"+
+		"    groups map<varchar, frozen<list<int>>>,\n"+
+		"    path list<tuple<double, double>>,\n"+
+		"    position tuple<double, double>,\n"+
+		"    tags set<varchar>,\n"+
+		"    PRIMARY KEY ((id ))\n"+
+		")\n;", stmt.Query())
+}
+
+type scalarRow struct {
+	Id       gocql.UUID `cql:"id,type=timeuuid"`
+	Tiny     int8
+	Small    int16
+	Unsigned uint32
+	Varint   *big.Int
+	Decimal  *inf.Dec
+	Address  net.IP
+	Period   gocql.Duration
+	Day      time.Time                  `cql:"day,type=date"`
+	Clock    time.Duration              `cql:"clock,type=time"`
+	Nested   map[string]map[string]bool `cql:"nested,type=map<text, frozen<map<text, boolean>>>"`
+}
+
+func TestCqlTypeOfScalars(t *testing.T) {
+	for _, tc := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{int8(1), "tinyint"},
+		{int16(1), "smallint"},
+		{uint8(1), "varint"},
+		{uint64(1), "varint"},
+		{big.NewInt(1), "varint"},
+		{inf.NewDec(1, 0), "decimal"},
+		{net.IPv4(127, 0, 0, 1), "inet"},
+		{gocql.Duration{}, "duration"},
+		{time.Second, "bigint"},
+		{[]int8{}, "list<tinyint>"},
+	} {
+		typ, err := stringTypeOf(tc.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, typ)
+	}
+
+	m, _ := toMap(scalarRow{})
+	ti := newTableInfo("ks1", "scalars", Keys{PartitionKeys: []string{"id"}}, scalarRow{}, m)
+	stmt, err := createTable("ks1", "scalars", ti.keys.PartitionKeys, nil, ti.fields, ti.fieldValues, ti.fieldOptions, nil, false, false, "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE ks1.scalars (\n"+
+		"    address inet,\n"+
+		"    decimal decimal,\n"+
+		"    period duration,\n"+
+		"    small smallint,\n"+
+		"    tiny tinyint,\n"+
+		"    unsigned varint,\n"+
+		"    varint varint,\n"+
+		"    clock time,\n"+
+		"    day date,\n"+
+		"    id timeuuid,\n"+
+		"    nested map<text, frozen<map<text, boolean>>>,\n"+
+		"    PRIMARY KEY ((id ))\n"+
+		")\n;", stmt.Query())
+}
+
+func TestCreateTableStaticColumns(t *testing.T) {
+	m, _ := toMap(userEvent{})
+	keys := Keys{PartitionKeys: []string{"UserId"}, ClusteringColumns: []string{"EventId"}}
+	ti := newTableInfo("ks1", "events", keys, userEvent{}, m)
+	stmt, err := createTable("ks1", "events", keys.PartitionKeys, keys.ClusteringColumns, ti.fields, ti.fieldValues, ti.fieldOptions, nil, false, false, "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE ks1.events (\n"+
+		"    displayname varchar STATIC,\n"+
+		"    eventid varchar,\n"+
+		"    kind varchar,\n"+
+		"    userid varchar,\n"+
+		"    PRIMARY KEY ((userid), eventid)\n"+
+		")\n;", stmt.Query())
+
+	_, err = createTable("ks1", "events", keys.PartitionKeys, nil, ti.fields, ti.fieldValues, ti.fieldOptions, nil, false, false, "", false)
+	assert.Error(t, err)
+
+	keys.ClusteringColumns = []string{"DisplayName"}
+	_, err = createTable("ks1", "events", keys.PartitionKeys, keys.ClusteringColumns, ti.fields, ti.fieldValues, ti.fieldOptions, nil, false, false, "", false)
+	assert.Error(t, err)
+}
+
+type quotedRow struct {
+	UserId string
+	Order  int
+	Key    string `cql:"key"`
+}
+
+func TestCreateTableQuotedIdentifiers(t *testing.T) {
+	m, _ := toMap(quotedRow{})
+	keys := Keys{PartitionKeys: []string{"UserId"}, ClusteringColumns: []string{"Order"}}
+	ti := newTableInfo("ks1", "table", keys, quotedRow{}, m)
+	order := []ClusteringOrderColumn{{Column: "Order", Direction: DESC}}
+	stmt, err := createTable("ks1", "table", keys.PartitionKeys, keys.ClusteringColumns, ti.fields, ti.fieldValues, ti.fieldOptions, order, false, false, "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE ks1.\"table\" (\n"+
+		"    \"order\" int,\n"+
+		"    userid varchar,\n"+
+		"    \"key\" varchar,\n"+
+		"    PRIMARY KEY ((userid), \"order\")\n"+
+		")\n"+
+		"WITH CLUSTERING ORDER BY (\"order\" DESC)\n;", stmt.Query())
+
+	stmt, err = createTable("ks1", "Events", keys.PartitionKeys, keys.ClusteringColumns, ti.fields, ti.fieldValues, ti.fieldOptions, order, false, false, "", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE ks1.\"Events\" (\n"+
+		"    \"Order\" int,\n"+
+		"    \"UserId\" varchar,\n"+
+		"    \"key\" varchar,\n"+
+		"    PRIMARY KEY ((\"UserId\"), \"Order\")\n"+
+		")\n"+
+		"WITH CLUSTERING ORDER BY (\"Order\" DESC)\n;", stmt.Query())
+}
+
+func TestCreateTypeStmts(t *testing.T) {
+	m, _ := toMap(customerWithAddress{})
+	ti := newTableInfo("ks1", "customers", Keys{PartitionKeys: []string{"Id"}}, customerWithAddress{}, m)
+	stmts, err := createTypeStmts("CREATE TYPE IF NOT EXISTS", "ks1", ti.fields, ti.fieldValues, ti.fieldOptions)
+	assert.NoError(t, err)
+	assert.Len(t, stmts, 2)
+	assert.Equal(t, "CREATE TYPE IF NOT EXISTS ks1.geopoint (\n    lat double,\n    lng double\n);", stmts[0].Query())
+	assert.Equal(t, "CREATE TYPE IF NOT EXISTS ks1.postaladdress (\n    street_name varchar,\n    city varchar,\n    location frozen<geopoint>\n);", stmts[1].Query())
+
+	stmt, err := createTable("ks1", "customers", ti.keys.PartitionKeys, nil, ti.fields, ti.fieldValues, ti.fieldOptions, nil, false, false, "", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE ks1.customers (\n"+
+		"    home frozen<postaladdress>,\n"+
+		"    id varchar,\n"+
+		"    locations map<varchar, frozen<geopoint>>,\n"+
+		"    previous list<frozen<postaladdress>>,\n"+
+		"    PRIMARY KEY ((id ))\n"+
+		")\n;", stmt.Query())
+}
+
+func TestCreateIfNotExistStatements(t *testing.T) {
+	ks := NewConnection(nil).KeySpace("ks1")
+	stmts, err := CreateIfNotExistStatements(
+		ks.MapTable("customers", "Id", customerWithAddress{}),
+		ks.MultimapTable("addresses", "City", "street_name", postalAddress{}),
+	)
+	assert.NoError(t, err)
+	var queries []string
+	for _, stmt := range stmts {
+		queries = append(queries, strings.SplitN(stmt.Query(), " (", 2)[0])
+	}
+	assert.Equal(t, []string{
+		"CREATE TYPE IF NOT EXISTS ks1.geopoint",
+		"CREATE TYPE IF NOT EXISTS ks1.postaladdress",
+		"CREATE TABLE IF NOT EXISTS ks1.customers_map_Id",
+		"CREATE TABLE IF NOT EXISTS ks1.addresses_multimap_City_street_name",
+	}, queries)
+}
+
+func TestUDTCodecRoundTrip(t *testing.T) {
+	native := func(typ gocql.Type) gocql.NativeType { return gocql.NewNativeType(0x04, typ, "") }
+	pointInfo := gocql.UDTTypeInfo{
+		NativeType: native(gocql.TypeUDT),
+		Name:       "geopoint",
+		Elements: []gocql.UDTField{
+			{Name: "lat", Type: native(gocql.TypeDouble)},
+			{Name: "lng", Type: native(gocql.TypeDouble)},
+		},
+	}
+	addressInfo := gocql.UDTTypeInfo{
+		NativeType: native(gocql.TypeUDT),
+		Name:       "postaladdress",
+		Elements: []gocql.UDTField{
+			{Name: "street_name", Type: native(gocql.TypeVarchar)},
+			{Name: "city", Type: native(gocql.TypeVarchar)},
+			{Name: "location", Type: pointInfo},
+		},
+	}
+	listInfo := gocql.CollectionType{NativeType: native(gocql.TypeList), Elem: addressInfo}
+	mapInfo := gocql.CollectionType{NativeType: native(gocql.TypeMap), Key: native(gocql.TypeVarchar), Elem: pointInfo}
+
+	in := customerWithAddress{
+		Home: postalAddress{Street: "1 Main St", City: "London", Location: geoPoint{51.5, -0.1}},
+		Previous: []postalAddress{
+			{Street: "2 High St", City: "Leeds"},
+			{Street: "3 Low Rd", City: "York", Location: geoPoint{53.9, -1.1}},
+		},
+		Locations: map[string]geoPoint{"work": {51.4, -0.2}},
+	}
+	values := wrapUDTValues([]interface{}{in.Id, in.Home, in.Previous, in.Locations})
+	assert.Equal(t, in.Id, values[0])
+
+	var out customerWithAddress
+	for i, info := range []gocql.TypeInfo{addressInfo, listInfo, mapInfo} {
+		data, err := gocql.Marshal(info, values[i+1])
+		assert.NoError(t, err)
+		dest := []interface{}{&out.Home, &out.Previous, &out.Locations}[i]
+		assert.NoError(t, gocql.Unmarshal(info, data, udtUnmarshaler{dest: reflect.ValueOf(dest)}))
+	}
+	assert.Equal(t, in, out)
+}
@@ -25,7 +25,7 @@ func (cb goCQLBackend) Query(stmt Statement, scanner Scanner) error {
 }
 
 func (cb goCQLBackend) QueryWithOptions(opts Options, stmt Statement, scanner Scanner) error {
-	qu := cb.session.Query(stmt.Query(), stmt.Values()...)
+	qu := cb.session.Query(stmt.Query(), wrapUDTValues(stmt.Values())...)
 	if opts.Consistency != nil {
 		qu = qu.Consistency(*opts.Consistency)
 	}
@@ -34,7 +34,7 @@ func (cb goCQLBackend) QueryWithOptions(opts Options, stmt Statement, scanner Sc
 	}
 
 	iter := qu.Iter()
-	if _, err := scanner.ScanIter(iter.Scanner()); err != nil {
+	if _, err := scanner.ScanIter(udtScannable{iter.Scanner()}); err != nil {
 		return err
 	}
 
@@ -46,7 +46,7 @@ func (cb goCQLBackend) Execute(stmt Statement) error {
 }
 
 func (cb goCQLBackend) ExecuteWithOptions(opts Options, stmt Statement) error {
-	qu := cb.session.Query(stmt.Query(), stmt.Values()...)
+	qu := cb.session.Query(stmt.Query(), wrapUDTValues(stmt.Values())...)
 	if opts.Consistency != nil {
 		qu = qu.Consistency(*opts.Consistency)
 	}
@@ -67,7 +67,7 @@ func (cb goCQLBackend) ExecuteAtomicallyWithOptions(opts Options, stmts []Statem
 	batch := cb.session.NewBatch(gocql.LoggedBatch)
 	for i := range stmts {
 		stmt := stmts[i]
-		batch.Query(stmt.Query(), stmt.Values()...)
+		batch.Query(stmt.Query(), wrapUDTValues(stmt.Values())...)
 	}
 
 	if opts.Consistency != nil {
@@ -0,0 +1,77 @@
+package gocassa
+
+import (
+	"regexp"
+	"strings"
+)
+
+var unquotedIdentifier = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// reservedKeywords are the CQL keywords which can't be used as unquoted
+// identifiers. "key" isn't reserved in recent versions of Cassandra, but it
+// is in older ones, so it is quoted as well.
+var reservedKeywords = map[string]bool{
+	"add": true, "allow": true, "alter": true, "and": true, "apply": true,
+	"asc": true, "authorize": true, "batch": true, "begin": true, "by": true,
+	"columnfamily": true, "create": true, "default": true, "delete": true,
+	"desc": true, "describe": true, "drop": true, "entries": true,
+	"execute": true, "from": true, "full": true, "grant": true, "if": true,
+	"in": true, "index": true, "infinity": true, "insert": true, "into": true,
+	"is": true, "key": true, "keyspace": true, "limit": true,
+	"materialized": true, "mbean": true, "mbeans": true, "modify": true,
+	"nan": true, "norecursive": true, "not": true, "null": true, "of": true,
+	"on": true, "or": true, "order": true, "primary": true, "rename": true,
+	"replace": true, "revoke": true, "schema": true, "select": true,
+	"set": true, "table": true, "to": true, "token": true, "truncate": true,
+	"unlogged": true, "unset": true, "update": true, "use": true,
+	"using": true, "view": true, "where": true, "with": true,
+}
+
+// identifier returns the CQL for a keyspace, table or column name, quoting it
+// when it is a reserved keyword or contains characters which aren't allowed
+// in unquoted identifiers.
+//
+// Unquoted identifiers are case insensitive in CQL, so unless caseSensitive is
+// set, names are matched case insensitively (and lowercased when they need to
+// be quoted). With caseSensitive set, names containing uppercase characters are
+// quoted so their case is preserved.
+func identifier(name string, caseSensitive bool) string {
+	lower := strings.ToLower(name)
+	if caseSensitive && name != lower {
+		return quoteIdentifier(name)
+	}
+	if unquotedIdentifier.MatchString(name) && !reservedKeywords[lower] {
+		return name
+	}
+	if caseSensitive {
+		return quoteIdentifier(name)
+	}
+	return quoteIdentifier(lower)
+}
+
+// columnName returns the CQL for a column name. Column names have always been
+// lowercased by gocassa, which is still the case unless caseSensitive is set.
+func columnName(name string, caseSensitive bool) string {
+	if !caseSensitive {
+		name = strings.ToLower(name)
+	}
+	return identifier(name, caseSensitive)
+}
+
+// columnNames applies columnName to each of the names
+func columnNames(names []string, caseSensitive bool) []string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = columnName(name, caseSensitive)
+	}
+	return out
+}
+
+// tableIdentifier returns the CQL for the keyspace qualified name of a table
+func tableIdentifier(keyspace, table string, caseSensitive bool) string {
+	return identifier(keyspace, caseSensitive) + "." + identifier(table, caseSensitive)
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
@@ -0,0 +1,330 @@
+package gocassa
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattheath/base62"
+	"github.com/mattheath/kala/bigflake"
+	"github.com/mattheath/kala/util"
+)
+
+// IDCodec decodes the ids of a flake series, which are strings encoding the
+// time they were generated at. It is set with the IDCodec option of the flake
+// series recipes, which use bigflake ids by default.
+type IDCodec interface {
+	// Time returns the time the id was generated at
+	Time(id string) (time.Time, error)
+	// Lexical returns whether the ids sort as text in the order they were
+	// generated, down to the precision of their time. ListSince compares ids
+	// as text in its query, so it can only be used with lexical ids.
+	Lexical() bool
+}
+
+// IDGenerator generates the ids decoded by an IDCodec
+type IDGenerator interface {
+	// NewID returns a new id with the given time, usually time.Now()
+	NewID(t time.Time) (string, error)
+}
+
+// sinceCodec returns the codec of the ids of a flake series table for a
+// ListSince, which needs ids sorting as text
+func sinceCodec(t Table) (IDCodec, error) {
+	codec := tableIDCodec(t)
+	if !codec.Lexical() {
+		return nil, errors.New("ListSince can't be used with ids which don't sort as text")
+	}
+	return codec, nil
+}
+
+// tableIDCodec returns the codec of the ids of a flake series table, which is
+// set with the IDCodec option of the table
+func tableIDCodec(t Table) IDCodec {
+	if c := t.Options().IDCodec; c != nil {
+		return c
+	}
+	return BigflakeIDs()
+}
+
+// TwitterEpoch is the epoch of Twitter's Snowflake ids
+var TwitterEpoch = time.Unix(1288834974, 657*int64(time.Millisecond))
+
+type bigflakeCodec struct{}
+
+// BigflakeIDs returns the codec of bigflake ids, which are 128 bit integers
+// encoded in base62 after a prefix and an underscore, for example
+// "user_1DQMRBWwBjDq5eHs4BRfCh". This is the codec of the flake series
+// recipes by default.
+func BigflakeIDs() IDCodec {
+	return bigflakeCodec{}
+}
+
+func (bigflakeCodec) Time(id string) (time.Time, error) {
+	n, err := bigflakeInt(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	msTime, _, _ := bigflake.ParseId(n)
+	return util.MsInt64ToTime(msTime), nil
+}
+
+func (bigflakeCodec) Lexical() bool {
+	return true
+}
+
+func bigflakeInt(id string) (*big.Int, error) {
+	parts := strings.Split(id, "_")
+	if len(parts) < 2 {
+		return nil, errors.New("Invalid flake id")
+	}
+	return base62.DecodeToBigInt(parts[len(parts)-1]), nil
+}
+
+type bigflakeGenerator struct {
+	sequencer
+	prefix string
+	worker int64
+}
+
+// NewBigflakeGenerator returns a generator of bigflake ids with the given
+// prefix. Generators running at the same time must have different workers,
+// of up to 48 bits.
+func NewBigflakeGenerator(prefix string, worker int64) IDGenerator {
+	return &bigflakeGenerator{prefix: prefix, worker: worker}
+}
+
+func (g *bigflakeGenerator) NewID(t time.Time) (string, error) {
+	if g.worker < 0 || g.worker >= 1<<48 {
+		return "", fmt.Errorf("Invalid bigflake worker: %d", g.worker)
+	}
+	ms := util.TimeToMsInt64(t)
+	ms, sequence, err := g.next(ms, 1<<16)
+	if err != nil {
+		return "", err
+	}
+	return g.prefix + "_" + base62.EncodeBigInt(bigflake.MintId(ms, g.worker, sequence)), nil
+}
+
+// sequencer numbers the ids generated in the same millisecond
+type sequencer struct {
+	mtx      sync.Mutex
+	started  bool
+	lastMs   int64
+	sequence int64
+}
+
+// next returns the millisecond and the sequence number of the next id of the
+// given millisecond, of which there can be up to max. Ids are never issued
+// under an earlier millisecond than the latest one, which keeps being used
+// if the clock goes backwards, so that ids don't repeat.
+func (s *sequencer) next(ms, max int64) (int64, int64, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if !s.started || ms > s.lastMs {
+		s.started, s.lastMs, s.sequence = true, ms, 0
+		return ms, 0, nil
+	}
+	if s.sequence+1 >= max {
+		return 0, 0, errors.New("Sequence overflow, too many ids generated in a millisecond")
+	}
+	s.sequence++
+	return s.lastMs, s.sequence, nil
+}
+
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type ulidCodec struct{}
+
+// ULIDs returns the codec of ULIDs, which are 26 characters of Crockford's
+// base32 encoding a 48 bit time in milliseconds followed by 80 random bits
+func ULIDs() IDCodec {
+	return ulidCodec{}
+}
+
+func (ulidCodec) Time(id string) (time.Time, error) {
+	if err := validULID(id); err != nil {
+		return time.Time{}, err
+	}
+	var ms int64
+	for _, c := range strings.ToUpper(id[:10]) {
+		ms = ms<<5 | int64(strings.IndexRune(ulidAlphabet, c))
+	}
+	return util.MsInt64ToTime(ms), nil
+}
+
+func (ulidCodec) Lexical() bool {
+	return true
+}
+
+func validULID(id string) error {
+	// The first character only holds 3 bits
+	if len(id) != 26 || id[0] > '7' {
+		return fmt.Errorf("Invalid ULID: %s", id)
+	}
+	for _, c := range strings.ToUpper(id) {
+		if !strings.ContainsRune(ulidAlphabet, c) {
+			return fmt.Errorf("Invalid ULID: %s", id)
+		}
+	}
+	return nil
+}
+
+type ulidGenerator struct{}
+
+// NewULIDGenerator returns a generator of ULIDs
+func NewULIDGenerator() IDGenerator {
+	return ulidGenerator{}
+}
+
+func (ulidGenerator) NewID(t time.Time) (string, error) {
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	ms := util.TimeToMsInt64(t)
+	if ms < 0 || ms >= 1<<48 {
+		return "", errors.New("Time is out of the range of ULIDs")
+	}
+	raw := make([]byte, 16)
+	binary.BigEndian.PutUint64(raw, uint64(ms)<<16)
+	copy(raw[6:], random)
+
+	// 128 bits are 26 characters of 5 bits, the first one holding 3 bits
+	n := new(big.Int).SetBytes(raw)
+	id := make([]byte, 26)
+	for i := len(id) - 1; i >= 0; i-- {
+		id[i] = ulidAlphabet[new(big.Int).And(n, big.NewInt(31)).Int64()]
+		n.Rsh(n, 5)
+	}
+	return string(id), nil
+}
+
+// ksuidEpoch is the epoch of the time of KSUIDs, in seconds
+const ksuidEpoch = 1400000000
+
+var ksuidEncoding = base62.NewStdEncoding().Option(base62.Padding(27))
+
+type ksuidCodec struct{}
+
+// KSUIDs returns the codec of KSUIDs, which are 27 characters of base62
+// encoding a 32 bit time in seconds followed by 128 random bits
+func KSUIDs() IDCodec {
+	return ksuidCodec{}
+}
+
+func (ksuidCodec) Time(id string) (time.Time, error) {
+	if err := validKSUID(id); err != nil {
+		return time.Time{}, err
+	}
+	n := base62.DecodeToBigInt(id)
+	secs := n.Rsh(n, 128).Int64()
+	return time.Unix(secs+ksuidEpoch, 0), nil
+}
+
+func (ksuidCodec) Lexical() bool {
+	return true
+}
+
+// maxKSUID is the largest KSUID, with all of its 160 bits set
+const maxKSUID = "aWgEPTl1tmebfsQzFP4bxwgy80V"
+
+func validKSUID(id string) error {
+	if len(id) != 27 || id > maxKSUID {
+		return fmt.Errorf("Invalid KSUID: %s", id)
+	}
+	for _, c := range id {
+		if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z') {
+			return fmt.Errorf("Invalid KSUID: %s", id)
+		}
+	}
+	return nil
+}
+
+type ksuidGenerator struct{}
+
+// NewKSUIDGenerator returns a generator of KSUIDs
+func NewKSUIDGenerator() IDGenerator {
+	return ksuidGenerator{}
+}
+
+func (ksuidGenerator) NewID(t time.Time) (string, error) {
+	secs := t.Unix() - ksuidEpoch
+	if secs < 0 || secs >= 1<<32 {
+		return "", errors.New("Time is out of the range of KSUIDs")
+	}
+	raw := make([]byte, 20)
+	binary.BigEndian.PutUint32(raw, uint32(secs))
+	if _, err := rand.Read(raw[4:]); err != nil {
+		return "", err
+	}
+	return ksuidEncoding.EncodeBigInt(new(big.Int).SetBytes(raw)), nil
+}
+
+type snowflakeCodec struct {
+	epoch time.Time
+}
+
+// SnowflakeIDs returns the codec of Snowflake ids, which are 64 bit integers
+// in decimal made of a 41 bit time in milliseconds since the given epoch, a
+// 10 bit worker and a 12 bit sequence. Twitter's ids use TwitterEpoch.
+// Snowflake ids don't sort as text once they gain a digit, so they can't be
+// used with ListSince.
+func SnowflakeIDs(epoch time.Time) IDCodec {
+	return snowflakeCodec{epoch: epoch}
+}
+
+func (c snowflakeCodec) Time(id string) (time.Time, error) {
+	n, err := snowflakeInt(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return c.epoch.Add(time.Duration(n>>22) * time.Millisecond), nil
+}
+
+func (snowflakeCodec) Lexical() bool {
+	return false
+}
+
+func snowflakeInt(id string) (int64, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("Invalid Snowflake id: %s", id)
+	}
+	return n, nil
+}
+
+type snowflakeGenerator struct {
+	sequencer
+	epoch  time.Time
+	worker int64
+}
+
+// NewSnowflakeGenerator returns a generator of Snowflake ids with the given
+// epoch. Generators running at the same time must have different workers,
+// from 0 to 1023.
+func NewSnowflakeGenerator(epoch time.Time, worker int64) IDGenerator {
+	return &snowflakeGenerator{epoch: epoch, worker: worker}
+}
+
+func (g *snowflakeGenerator) NewID(t time.Time) (string, error) {
+	if g.worker < 0 || g.worker >= 1<<10 {
+		return "", fmt.Errorf("Invalid Snowflake worker: %d", g.worker)
+	}
+	ms := int64(t.Sub(g.epoch) / time.Millisecond)
+	if ms < 0 || ms >= 1<<41 {
+		return "", errors.New("Time is out of the range of Snowflake ids")
+	}
+	ms, sequence, err := g.next(ms, 1<<12)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(ms<<22|g.worker<<12|sequence, 10), nil
+}
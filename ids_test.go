@@ -0,0 +1,117 @@
+package gocassa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDCodecs(t *testing.T) {
+	at := time.Date(2021, 2, 3, 4, 5, 6, 789*int(time.Millisecond), time.UTC)
+	for name, tc := range map[string]struct {
+		codec     IDCodec
+		generator IDGenerator
+		precision time.Duration
+		lexical   bool
+	}{
+		"bigflake":  {BigflakeIDs(), NewBigflakeGenerator("id", 1), time.Millisecond, true},
+		"ULID":      {ULIDs(), NewULIDGenerator(), time.Millisecond, true},
+		"KSUID":     {KSUIDs(), NewKSUIDGenerator(), time.Second, true},
+		"Snowflake": {SnowflakeIDs(TwitterEpoch), NewSnowflakeGenerator(TwitterEpoch, 1), time.Millisecond, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			first, err := tc.generator.NewID(at)
+			require.NoError(t, err)
+			second, err := tc.generator.NewID(at.Add(tc.precision))
+			require.NoError(t, err)
+
+			ts, err := tc.codec.Time(first)
+			require.NoError(t, err)
+			assert.True(t, at.Truncate(tc.precision).Equal(ts), ts)
+
+			assert.Equal(t, tc.lexical, tc.codec.Lexical())
+			assert.True(t, first < second)
+
+			_, err = tc.codec.Time("not an id")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSnowflakeIDsOfDifferentLengths(t *testing.T) {
+	// The ids of these milliseconds are on either side of 10^18, so the
+	// second one has a digit more and sorts before the first one as text
+	epoch := TwitterEpoch
+	before := epoch.Add(238418579101 * time.Millisecond)
+	after := before.Add(time.Millisecond)
+
+	first, err := NewSnowflakeGenerator(epoch, 0).NewID(before)
+	require.NoError(t, err)
+	second, err := NewSnowflakeGenerator(epoch, 0).NewID(after)
+	require.NoError(t, err)
+	assert.Len(t, first, 18)
+	assert.Len(t, second, 19)
+	assert.True(t, second < first)
+
+	codec := SnowflakeIDs(epoch)
+	ts, err := codec.Time(second)
+	require.NoError(t, err)
+	assert.True(t, after.Truncate(time.Millisecond).Equal(ts), ts)
+
+	// ListSince would miss the second id, so it refuses Snowflake ids
+	assert.False(t, codec.Lexical())
+	tbl := NewConnection(nil).KeySpace("ks1").FlakeSeriesTable("flake", "Id", time.Hour, flake{}).
+		WithOptions(Options{IDCodec: codec})
+	assert.EqualError(t, tbl.ListSince(first, time.Hour, &[]flake{}).Run(), "ListSince can't be used with ids which don't sort as text")
+}
+
+func TestIDCodecsDecodeKnownIDs(t *testing.T) {
+	ts, err := ULIDs().Time("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1469922850259), ts.UnixNano()/int64(time.Millisecond))
+
+	ts, err = KSUIDs().Time("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2017, 10, 10, 4, 0, 47, 0, time.UTC).Equal(ts), ts)
+
+	// Snowflakes of the same millisecond differ in their sequence
+	generator := NewSnowflakeGenerator(TwitterEpoch, 5)
+	a, err := generator.NewID(TwitterEpoch.Add(time.Hour))
+	assert.NoError(t, err)
+	b, err := generator.NewID(TwitterEpoch.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "15099494420480", a)
+	assert.Equal(t, "15099494420481", b)
+
+	_, err = NewSnowflakeGenerator(TwitterEpoch, 1024).NewID(TwitterEpoch)
+	assert.Error(t, err)
+}
+
+func TestIDGeneratorsClockGoingBackwards(t *testing.T) {
+	at := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	for name, tc := range map[string]struct {
+		codec     IDCodec
+		generator IDGenerator
+	}{
+		"bigflake":  {BigflakeIDs(), NewBigflakeGenerator("id", 1)},
+		"Snowflake": {SnowflakeIDs(TwitterEpoch), NewSnowflakeGenerator(TwitterEpoch, 1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			first, err := tc.generator.NewID(at)
+			require.NoError(t, err)
+			second, err := tc.generator.NewID(at.Add(time.Second))
+			require.NoError(t, err)
+			third, err := tc.generator.NewID(at)
+			require.NoError(t, err)
+
+			// The id of the earlier time is issued under the latest millisecond
+			assert.NotEqual(t, first, third)
+			assert.NotEqual(t, second, third)
+			ts, err := tc.codec.Time(third)
+			require.NoError(t, err)
+			assert.True(t, at.Add(time.Second).Equal(ts), ts)
+		})
+	}
+}
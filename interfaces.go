@@ -2,7 +2,10 @@ package gocassa
 
 import (
 	"context"
+	"encoding/json"
 	"time"
+
+	"github.com/gocql/gocql"
 )
 
 // Connection exists because one can not connect to a keyspace if it does not exist, thus having a Create on KeySpace is not possible.
@@ -33,8 +36,18 @@ type KeySpace interface {
 		TimeSeriesTable lets you list rows which have a field value between two date ranges.
 		timeField is used as the partition key alongside the bucket.
 		bucketSize is used to determine for what duration the data will be stored on the same partition.
+		The Bucketing option replaces the fixed size buckets of the time series recipes, for example with
+		calendar months.
 	*/
 	TimeSeriesTable(prefixForTableName, timeField, clusteringKey string, bucketSize time.Duration, rowDefinition interface{}) TimeSeriesTable
+	/*
+		ShardedTimeSeriesTable is a TimeSeriesTable whose buckets are split into shards, to spread the writes
+		of a bucket over several partitions. The bucket and the shard, which is derived from a hash of the
+		clusteringKey marshalled as a value of its column, make up the composite partitionKey. Reads query every
+		shard of a bucket and merge the rows by timeField. The number of shards is part of the table name, so
+		changing it means rewriting the rows into a new table.
+	*/
+	ShardedTimeSeriesTable(prefixForTableName, timeField, clusteringKey string, shards int, bucketSize time.Duration, rowDefinition interface{}) TimeSeriesTable
 	/*
 		MultiTimeSeriesTable is a cross between TimeSeries and Multimap tables.
 		The partitionKey and timeField make up the composite partitionKey.
@@ -54,19 +67,52 @@ type KeySpace interface {
 		flakeIDField is used as the partition key along with the bucket field.
 		(FlakeIDs encode time of ID generation within them and can be used as a replacement for timestamps)
 		bucketSize is used to determine for what duration the data will be stored on the same partition.
+		The IDCodec option selects the format of the IDs, for example ULIDs, which are bigflake IDs by default.
 	*/
 	FlakeSeriesTable(prefixForTableName, flakeIDField string, bucketSize time.Duration, rowDefinition interface{}) FlakeSeriesTable
 	MultiFlakeSeriesTable(prefixForTableName, partitionKey, flakeIDField string, bucketSize time.Duration, rowDefinition interface{}) MultiFlakeSeriesTable
+	/*
+		MultiKeyFlakeSeriesTable is a cross between FlakeSeries and MultimapMultikey tables.
+		The partitionKeys and the bucket make up the composite partitionKey.
+	*/
+	MultiKeyFlakeSeriesTable(prefixForTableName string, partitionKeys []string, flakeIDField string, bucketSize time.Duration, rowDefinition interface{}) MultiKeyFlakeSeriesTable
+	/*
+		TimeUUIDSeriesTable is similar to FlakeSeriesTable, for rows keyed by a version 1 (time based) UUID.
+		The bucket of the time of the timeUUIDField is the partition key, and the rows of a partition are
+		ordered by the timeUUIDField, which is declared as a timeuuid column for Cassandra to order them by
+		time, without needing a type=timeuuid tag option.
+		bucketSize is used to determine for what duration the data will be stored on the same partition.
+	*/
+	TimeUUIDSeriesTable(prefixForTableName, timeUUIDField string, bucketSize time.Duration, rowDefinition interface{}) TimeUUIDSeriesTable
+	/*
+		MultiKeyTimeUUIDSeriesTable is a cross between TimeUUIDSeries and MultimapMultikey tables.
+		The partitionKeys and the bucket make up the composite partitionKey.
+	*/
+	MultiKeyTimeUUIDSeriesTable(prefixForTableName string, partitionKeys []string, timeUUIDField string, bucketSize time.Duration, rowDefinition interface{}) MultiKeyTimeUUIDSeriesTable
 	Table(prefixForTableName string, rowDefinition interface{}, keys Keys) Table
+	// TableFromTags is like Table but derives the keys and clustering order
+	// from the cql tags of the row definition, see KeysFromTags.
+	TableFromTags(prefixForTableName string, rowDefinition interface{}) (Table, error)
 	// DebugMode enables/disables debug mode depending on the value of the input boolean.
 	// When DebugMode is enabled, all built CQL statements are printe to stdout.
 	DebugMode(bool)
+	// SetTableNaming sets the strategy used to name tables created afterwards,
+	// LegacyTableNames by default.
+	SetTableNaming(TableNaming)
+	// TableNameMapping returns the legacy names of the tables created with
+	// SafeTableNames mapped to the names they were actually given.
+	TableNameMapping() map[string]string
 	// Name returns the keyspace name as in C*
 	Name() string
 	// Tables returns the name of all configured column families in this keyspace
 	Tables() ([]string, error)
 	// Exists returns whether the specified column family exists within the keyspace
 	Exists(string) (bool, error)
+	// RegisteredTables returns every table created through this keyspace, in
+	// the order they were first created. A table is replaced by the table
+	// returned by its WithOptions, so that it has the options it is used with.
+	// See SchemaScript.
+	RegisteredTables() []TableChanger
 }
 
 //
@@ -96,9 +142,15 @@ type MultimapTable interface {
 	// will be deleted. To only overwrite some of the fields, Update()
 	Set(rowStruct interface{}) Op
 	Update(value, id interface{}, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row
+	// with the same value, without needing an id
+	UpdateStatic(value interface{}, valuesToUpdate map[string]interface{}) Op
 	Delete(value, id interface{}) Op
 	DeleteAll(value interface{}) Op
 	List(partitionKey, clusteringKey interface{}, limit int, pointerToASlice interface{}) Op
+	// ListRange lists up to limit rows with ids in the given range, which can
+	// exclude its start to page through the rows and list them in descending order
+	ListRange(partitionKey interface{}, r IDRange, limit int, pointerToASlice interface{}) Op
 	Read(partitionKey, clusteringKey, pointer interface{}) Op
 	MultiRead(partitionKey interface{}, ids []interface{}, pointerToASlice interface{}) Op
 	WithOptions(Options) MultimapTable
@@ -111,9 +163,15 @@ type MultimapMkTable interface {
 	// will be deleted. To only overwrite some of the fields, Update()
 	Set(rowStruct interface{}) Op
 	Update(v, id map[string]interface{}, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row
+	// with the same values, without needing an id
+	UpdateStatic(v map[string]interface{}, valuesToUpdate map[string]interface{}) Op
 	Delete(v, id map[string]interface{}) Op
 	DeleteAll(v map[string]interface{}) Op
 	List(v, startId map[string]interface{}, limit int, pointerToASlice interface{}) Op
+	// ListRange lists up to limit rows with ids in the given range, comparing ids
+	// lexicographically in the order of the clustering columns
+	ListRange(v map[string]interface{}, r MkIDRange, limit int, pointerToASlice interface{}) Op
 	Read(v, id map[string]interface{}, pointer interface{}) Op
 	MultiRead(v, id map[string]interface{}, pointerToASlice interface{}) Op
 	WithOptions(Options) MultimapMkTable
@@ -132,10 +190,20 @@ type TimeSeriesTable interface {
 	// will be deleted. To only overwrite some of the fields, Update()
 	Set(rowStruct interface{}) Op
 	Update(timeStamp time.Time, id interface{}, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row of
+	// the bucket of the given time, without needing an id
+	UpdateStatic(timeStamp time.Time, valuesToUpdate map[string]interface{}) Op
 	Delete(timeStamp time.Time, id interface{}) Op
 	Read(timeStamp time.Time, id, pointer interface{}) Op
 	List(start, end time.Time, pointerToASlice interface{}) Op
+	// ListLatest lists the latest rows before the given time, but not before start, newest first.
+	// It reads the buckets one by one from the newest, and stops once it has read limit rows. A
+	// limit of 0 reads all the rows.
+	ListLatest(start, before time.Time, limit int, pointerToASlice interface{}) Op
 	Buckets(start time.Time) Buckets
+	// Retention deletes the buckets from the one of start up to the last one ending before or at
+	// cutoff with the given BucketRetention, and returns the start of each of them
+	Retention(ctx context.Context, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error)
 	WithOptions(Options) TimeSeriesTable
 	Table() Table
 	TableChanger
@@ -152,10 +220,20 @@ type MultiTimeSeriesTable interface {
 	// will be deleted. To only overwrite some of the fields, Update()
 	Set(rowStruct interface{}) Op
 	Update(v interface{}, timeStamp time.Time, id interface{}, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row of
+	// the bucket of the given time, without needing an id
+	UpdateStatic(v interface{}, timeStamp time.Time, valuesToUpdate map[string]interface{}) Op
 	Delete(v interface{}, timeStamp time.Time, id interface{}) Op
 	Read(v interface{}, timeStamp time.Time, id, pointer interface{}) Op
 	List(v interface{}, start, end time.Time, pointerToASlice interface{}) Op
+	// ListLatest lists the latest rows before the given time, but not before start, newest first.
+	// It reads the buckets one by one from the newest, and stops once it has read limit rows. A
+	// limit of 0 reads all the rows.
+	ListLatest(v interface{}, start, before time.Time, limit int, pointerToASlice interface{}) Op
 	Buckets(v interface{}, start time.Time) Buckets
+	// Retention deletes the buckets of v from the one of start up to the last one ending before or
+	// at cutoff with the given BucketRetention, and returns the start of each of them
+	Retention(ctx context.Context, v interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error)
 	WithOptions(Options) MultiTimeSeriesTable
 	Table() Table
 	TableChanger
@@ -169,10 +247,16 @@ type MultiKeyTimeSeriesTable interface {
 	// will be deleted. To only overwrite some of the fields, Update()
 	Set(rowStruct interface{}) Op
 	Update(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row of
+	// the bucket of the given time, without needing an id
+	UpdateStatic(v map[string]interface{}, timeStamp time.Time, valuesToUpdate map[string]interface{}) Op
 	Delete(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}) Op
 	Read(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, pointer interface{}) Op
 	List(v map[string]interface{}, start, end time.Time, pointerToASlice interface{}) Op
 	Buckets(v map[string]interface{}, start time.Time) Buckets
+	// Retention deletes the buckets of v from the one of start up to the last one ending before or
+	// at cutoff with the given BucketRetention, and returns the start of each of them
+	Retention(ctx context.Context, v map[string]interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error)
 	WithOptions(Options) MultiKeyTimeSeriesTable
 	Table() Table
 	TableChanger
@@ -183,12 +267,19 @@ type FlakeSeriesTable interface {
 	// will be deleted. To only overwrite some of the fields, Update()
 	Set(rowStruct interface{}) Op
 	Update(id string, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row of
+	// the bucket of the given time, without needing an id
+	UpdateStatic(timeStamp time.Time, valuesToUpdate map[string]interface{}) Op
 	Delete(id string) Op
 	Read(id string, pointer interface{}) Op
 	List(start, end time.Time, pointerToASlice interface{}) Op
 	Buckets(start time.Time) Buckets
+	// Retention deletes the buckets from the one of start up to the last one ending before or at
+	// cutoff with the given BucketRetention, and returns the start of each of them
+	Retention(ctx context.Context, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error)
 	// ListSince queries the flakeSeries for the items after the specified ID but within the time window,
-	// if the time window is zero then it lists up until 5 minutes in the future
+	// if the time window is zero then it lists up until 5 minutes in the future. It compares IDs as text,
+	// so it fails with an IDCodec whose IDs aren't Lexical.
 	ListSince(id string, window time.Duration, pointerToASlice interface{}) Op
 	WithOptions(Options) FlakeSeriesTable
 	Table() Table
@@ -200,18 +291,100 @@ type MultiFlakeSeriesTable interface {
 	// will be deleted. To only overwrite some of the fields, Update()
 	Set(rowStruct interface{}) Op
 	Update(v interface{}, id string, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row of
+	// the bucket of the given time, without needing an id
+	UpdateStatic(v interface{}, timeStamp time.Time, valuesToUpdate map[string]interface{}) Op
 	Delete(v interface{}, id string) Op
 	Read(v interface{}, id string, pointer interface{}) Op
 	List(v interface{}, start, end time.Time, pointerToASlice interface{}) Op
 	Buckets(v interface{}, start time.Time) Buckets
+	// Retention deletes the buckets of v from the one of start up to the last one ending before or
+	// at cutoff with the given BucketRetention, and returns the start of each of them
+	Retention(ctx context.Context, v interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error)
 	// ListSince queries the flakeSeries for the items after the specified ID but within the time window,
-	// if the time window is zero then it lists up until 5 minutes in the future
+	// if the time window is zero then it lists up until 5 minutes in the future. It compares IDs as text,
+	// so it fails with an IDCodec whose IDs aren't Lexical.
 	ListSince(v interface{}, id string, window time.Duration, pointerToASlice interface{}) Op
 	WithOptions(Options) MultiFlakeSeriesTable
 	Table() Table
 	TableChanger
 }
 
+// MultiKeyFlakeSeriesTable is a cross between FlakeSeries and MultimapMkTable tables.
+type MultiKeyFlakeSeriesTable interface {
+	// Set Inserts, or Replaces your row with the supplied struct. Be aware that what is not in your struct
+	// will be deleted. To only overwrite some of the fields, Update()
+	Set(rowStruct interface{}) Op
+	Update(v map[string]interface{}, id string, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row of
+	// the bucket of the given time, without needing an id
+	UpdateStatic(v map[string]interface{}, timeStamp time.Time, valuesToUpdate map[string]interface{}) Op
+	Delete(v map[string]interface{}, id string) Op
+	Read(v map[string]interface{}, id string, pointer interface{}) Op
+	List(v map[string]interface{}, start, end time.Time, pointerToASlice interface{}) Op
+	Buckets(v map[string]interface{}, start time.Time) Buckets
+	// Retention deletes the buckets of v from the one of start up to the last one ending before or
+	// at cutoff with the given BucketRetention, and returns the start of each of them
+	Retention(ctx context.Context, v map[string]interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error)
+	// ListSince queries the flakeSeries for the items after the specified ID but within the time window,
+	// if the time window is zero then it lists up until 5 minutes in the future. It compares IDs as text,
+	// so it fails with an IDCodec whose IDs aren't Lexical.
+	ListSince(v map[string]interface{}, id string, window time.Duration, pointerToASlice interface{}) Op
+	WithOptions(Options) MultiKeyFlakeSeriesTable
+	Table() Table
+	TableChanger
+}
+
+// TimeUUIDSeriesTable is a time series of rows keyed by time UUIDs
+type TimeUUIDSeriesTable interface {
+	// Set Inserts, or Replaces your row with the supplied struct. Be aware that what is not in your struct
+	// will be deleted. To only overwrite some of the fields, Update()
+	Set(rowStruct interface{}) Op
+	Update(id gocql.UUID, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row of
+	// the bucket of the given time, without needing an id
+	UpdateStatic(timeStamp time.Time, valuesToUpdate map[string]interface{}) Op
+	Delete(id gocql.UUID) Op
+	Read(id gocql.UUID, pointer interface{}) Op
+	// List lists the rows whose id has a time from start up to, but not including, end
+	List(start, end time.Time, pointerToASlice interface{}) Op
+	Buckets(start time.Time) Buckets
+	// Retention deletes the buckets from the one of start up to the last one ending before or at
+	// cutoff with the given BucketRetention, and returns the start of each of them
+	Retention(ctx context.Context, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error)
+	// ListSince queries the series for the items after the specified ID but within the time window,
+	// if the time window is zero then it lists up until 5 minutes in the future
+	ListSince(id gocql.UUID, window time.Duration, pointerToASlice interface{}) Op
+	WithOptions(Options) TimeUUIDSeriesTable
+	Table() Table
+	TableChanger
+}
+
+// MultiKeyTimeUUIDSeriesTable is a cross between TimeUUIDSeries and MultimapMkTable tables.
+type MultiKeyTimeUUIDSeriesTable interface {
+	// Set Inserts, or Replaces your row with the supplied struct. Be aware that what is not in your struct
+	// will be deleted. To only overwrite some of the fields, Update()
+	Set(rowStruct interface{}) Op
+	Update(v map[string]interface{}, id gocql.UUID, valuesToUpdate map[string]interface{}) Op
+	// UpdateStatic updates static columns, which are shared by every row of
+	// the bucket of the given time, without needing an id
+	UpdateStatic(v map[string]interface{}, timeStamp time.Time, valuesToUpdate map[string]interface{}) Op
+	Delete(v map[string]interface{}, id gocql.UUID) Op
+	Read(v map[string]interface{}, id gocql.UUID, pointer interface{}) Op
+	// List lists the rows whose id has a time from start up to, but not including, end
+	List(v map[string]interface{}, start, end time.Time, pointerToASlice interface{}) Op
+	Buckets(v map[string]interface{}, start time.Time) Buckets
+	// Retention deletes the buckets of v from the one of start up to the last one ending before or
+	// at cutoff with the given BucketRetention, and returns the start of each of them
+	Retention(ctx context.Context, v map[string]interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error)
+	// ListSince queries the series for the items after the specified ID but within the time window,
+	// if the time window is zero then it lists up until 5 minutes in the future
+	ListSince(v map[string]interface{}, id gocql.UUID, window time.Duration, pointerToASlice interface{}) Op
+	WithOptions(Options) MultiKeyTimeUUIDSeriesTable
+	Table() Table
+	TableChanger
+}
+
 //
 // Raw CQL
 //
@@ -221,12 +394,24 @@ type MultiFlakeSeriesTable interface {
 type Filter interface {
 	// Update does a partial update. Use this if you don't want to overwrite your whole row, but you want to modify fields atomically.
 	Update(valuesToUpdate map[string]interface{}) Op // Probably this is danger zone (can't be implemented efficiently) on a selectuinb with more than 1 document
-	// Delete all rows matching the filter.
+	// Delete all rows matching the filter. Filtering on a range of clustering columns deletes the
+	// rows within the range.
 	Delete() Op
+	// DeleteColumns deletes the given columns, or elements of map and list columns, of all rows
+	// matching the filter. The rows themselves are kept.
+	DeleteColumns(columns ...DeleteColumn) Op
 	// Reads all results. Make sure you pass in a pointer to a slice.
 	Read(pointerToASlice interface{}) Op
 	// ReadOne reads a single result. Make sure you pass in a pointer.
 	ReadOne(pointer interface{}) Op
+	// ReadJSON reads all results as JSON objects, with the same column names and value formats as
+	// Cassandra's SELECT JSON.
+	ReadJSON(out *[]json.RawMessage) Op
+	// Count reads the number of rows matching the filter.
+	Count(count *int64) Op
+	// Aggregate reads the result of an aggregate function (such as AggregateMax) applied to a field
+	// of the rows matching the filter. Make sure you pass in a pointer of a type matching the result.
+	Aggregate(fn AggregateFunc, field string, pointer interface{}) Op
 	// Table on which this filter operates.
 	Table() Table
 	// Relations which make up this filter. These should not be modified.
@@ -296,6 +481,9 @@ type TableChanger interface {
 	CreateIfNotExist() error
 	// CreateStatement returns you the CQL query which can be used to create the table manually in cqlsh
 	CreateIfNotExistStatement() (Statement, error)
+	// CreateTypeStatements returns the CQL queries which create the user-defined types used by the table.
+	// These have to be run before the table itself is created.
+	CreateTypeStatements() ([]Statement, error)
 	// Recreate drops the table if exists and creates it again.
 	// This is useful for test purposes only.
 	Recreate() error
@@ -312,8 +500,16 @@ type Table interface {
 	Set(rowStruct interface{}) Op
 	// Where accepts a bunch of realtions and returns a filter. See the documentation for Relation and Filter to understand what that means.
 	Where(relations ...Relation) Filter // Because we provide selections
+	// SetJSON inserts, or replaces the row given as a JSON object (INSERT INTO ... JSON). Columns
+	// missing from the object are set to null, unless the DefaultUnset option is set.
+	SetJSON(row []byte) Op
+	// DistinctPartitionKeys reads the partition keys of all partitions of the table, without
+	// reading their rows. Only the partition key fields of the results are set.
+	DistinctPartitionKeys(pointerToASlice interface{}) Op
 	// Name returns the underlying table name, as stored in C*
 	WithOptions(Options) Table
+	// Options returns the options of the table
+	Options() Options
 	TableChanger
 }
 
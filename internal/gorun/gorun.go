@@ -0,0 +1,48 @@
+// Package gorun runs small generated programs which import a package of the
+// user's module, which is how the commands inspect tables declared in Go code.
+package gorun
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ImportPath returns the import path of the package in dir.
+func ImportPath(dir string) (string, error) {
+	out, err := goCommand(dir, "list", "-f", "{{.ImportPath}}", ".")
+	return strings.TrimSpace(string(out)), err
+}
+
+// Run compiles and runs the source of a main package from within dir, and
+// returns its standard output.
+func Run(dir string, src []byte) ([]byte, error) {
+	// The program has to be inside the module so that it can import packages
+	// from it, directories starting with an underscore are otherwise ignored
+	// by the go tool
+	tmpDir, err := os.MkdirTemp(dir, "_gocassa_run")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), src, 0644); err != nil {
+		return nil, err
+	}
+	return goCommand(dir, "run", "./"+filepath.Base(tmpDir))
+}
+
+func goCommand(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	stderr := bytes.Buffer{}
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go %v: %v\n%s", strings.Join(args, " "), err, stderr.Bytes())
+	}
+	return out, nil
+}
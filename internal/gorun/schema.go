@@ -0,0 +1,51 @@
+package gorun
+
+import (
+	"bytes"
+	"text/template"
+)
+
+var schemaProgram = template.Must(template.New("schema").Parse(`package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rkilburn/gocassa"
+	target {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	ks := gocassa.NewConnection(nil).KeySpace({{printf "%q" .KeySpace}})
+	target.{{.Func}}(ks)
+	script, err := gocassa.SchemaScript(ks, {{printf "%q" .Replication}})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Print(script)
+}
+`))
+
+// SchemaScript returns the CQL script creating the keyspace, types and tables
+// created by the function fn of the package in dir, which takes a
+// gocassa.KeySpace as its only argument. The replication options of the
+// keyspace default to SimpleStrategy with a replication factor of 1.
+func SchemaScript(dir, fn, keySpace, replication string) ([]byte, error) {
+	importPath, err := ImportPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	src := bytes.Buffer{}
+	err = schemaProgram.Execute(&src, map[string]string{
+		"ImportPath":  importPath,
+		"Func":        fn,
+		"KeySpace":    keySpace,
+		"Replication": replication,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Run(dir, src.Bytes())
+}
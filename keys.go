@@ -0,0 +1,82 @@
+package gocassa
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	r "github.com/rkilburn/gocassa/reflect"
+)
+
+// KeysFromTags derives the Keys of a table from the cql tags of the row
+// struct. Partition keys are declared with the "pk" option and clustering
+// columns with the "ck" option, optionally followed by their clustering
+// order, in the order in which the fields are declared. For example:
+//
+//	type Event struct {
+//		UserId  string    `cql:"user_id,pk"`
+//		Created time.Time `cql:"created,ck=desc"`
+//		EventId string    `cql:"event_id,ck"`
+//	}
+//
+// The returned Options contain the clustering order of the table if any of
+// the clustering columns is descending.
+func KeysFromTags(rowDefinition interface{}) (Keys, Options, error) {
+	keys, opts := Keys{}, Options{}
+
+	typ := reflect.TypeOf(rowDefinition)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return keys, opts, fmt.Errorf("row definition must be a struct, got %T", rowDefinition)
+	}
+
+	fields, err := r.StructFields(typ)
+	if err != nil {
+		return keys, opts, err
+	}
+
+	order := []ClusteringOrderColumn{}
+	hasDesc := false
+	for _, f := range fields {
+		if _, ok := f.Option("pk"); ok {
+			return keys, opts, fmt.Errorf("the pk option of field %v doesn't take a value", f.Name())
+		}
+		pk := f.HasOption("pk")
+		direction, ck := f.Option("ck")
+		ck = ck || f.HasOption("ck")
+
+		switch {
+		case !pk && !ck:
+			continue
+		case pk && ck:
+			return keys, opts, fmt.Errorf("field %v is declared as both a partition key and a clustering column", f.Name())
+		case f.HasOption("static"):
+			return keys, opts, fmt.Errorf("key field %v can't be static", f.Name())
+		case pk:
+			keys.PartitionKeys = append(keys.PartitionKeys, f.Name())
+			continue
+		}
+
+		col := ClusteringOrderColumn{Column: f.Name(), Direction: ASC}
+		switch strings.ToLower(direction) {
+		case "", "asc":
+		case "desc":
+			col.Direction = DESC
+			hasDesc = true
+		default:
+			return keys, opts, fmt.Errorf("invalid clustering order %q for field %v, expected asc or desc", direction, f.Name())
+		}
+		keys.ClusteringColumns = append(keys.ClusteringColumns, f.Name())
+		order = append(order, col)
+	}
+
+	if len(keys.PartitionKeys) == 0 {
+		return keys, opts, fmt.Errorf("no partition key declared in the cql tags of %v", typ)
+	}
+	if hasDesc {
+		opts.ClusteringOrder = order
+	}
+	return keys, opts, nil
+}
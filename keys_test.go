@@ -0,0 +1,74 @@
+package gocassa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type taggedEvent struct {
+	UserId  string    `cql:"user_id,pk"`
+	Region  string    `cql:"region,pk"`
+	Created time.Time `cql:"created,ck=desc"`
+	EventId string    `cql:"event_id,ck"`
+	Body    string    `cql:"body"`
+}
+
+func TestKeysFromTags(t *testing.T) {
+	keys, opts, err := KeysFromTags(&taggedEvent{})
+	assert.NoError(t, err)
+	assert.Equal(t, Keys{
+		PartitionKeys:     []string{"user_id", "region"},
+		ClusteringColumns: []string{"created", "event_id"},
+	}, keys)
+	assert.Equal(t, []ClusteringOrderColumn{{DESC, "created"}, {ASC, "event_id"}}, opts.ClusteringOrder)
+
+	keys, opts, err = KeysFromTags(struct {
+		Id   string `cql:"id,pk"`
+		Name string `cql:"name,ck=ASC"`
+	}{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name"}, keys.ClusteringColumns)
+	assert.Nil(t, opts.ClusteringOrder)
+
+	for _, row := range []interface{}{
+		"not a struct",
+		struct{ Id string }{},
+		struct {
+			Id string `cql:"id,pk,ck"`
+		}{},
+		struct {
+			Id string `cql:"id,pk=1"`
+		}{},
+		struct {
+			Id   string `cql:"id,pk"`
+			Name string `cql:"name,ck=sideways"`
+		}{},
+		struct {
+			Id   string `cql:"id,pk"`
+			Name string `cql:"name,ck,static"`
+		}{},
+	} {
+		_, _, err := KeysFromTags(row)
+		assert.Error(t, err, "%#v", row)
+	}
+}
+
+func TestTableFromTags(t *testing.T) {
+	ks := NewMockKeySpace()
+	tbl, err := ks.TableFromTags("events", taggedEvent{})
+	assert.NoError(t, err)
+	assert.Equal(t, "events__user_id_region__created_event_id", tbl.Name())
+
+	now := time.Now().Truncate(time.Second)
+	for i, id := range []string{"a", "b", "c"} {
+		assert.NoError(t, tbl.Set(taggedEvent{"u1", "eu", now.Add(time.Duration(i) * time.Minute), id, "hello"}).Run())
+	}
+	var events []taggedEvent
+	assert.NoError(t, tbl.Where(Eq("user_id", "u1"), Eq("region", "eu")).Read(&events).Run())
+	assert.Len(t, events, 3)
+
+	_, err = ks.TableFromTags("events", struct{ Id string }{})
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package gocassa
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,6 +16,78 @@ type k struct {
 	name         string
 	debugMode    bool
 	tableFactory tableFactory
+	naming       TableNaming
+	registry     tableRegistry
+}
+
+// tableRegistry records the tables created through a keyspace so that their
+// schema can be exported. Tables are identified by name, so creating the same
+// table again doesn't register it twice.
+type tableRegistry struct {
+	sync.Mutex
+	tables  []TableChanger
+	names   map[string]bool
+	renamed map[string]string // legacy table names to their actual name
+}
+
+func (r *tableRegistry) register(table TableChanger) {
+	r.Lock()
+	defer r.Unlock()
+	if r.names == nil {
+		r.names = map[string]bool{}
+	}
+	if r.names[table.Name()] {
+		return
+	}
+	r.names[table.Name()] = true
+	r.tables = append(r.tables, table)
+}
+
+// replace replaces a registered table by the table returned by its WithOptions,
+// so that the exported schema has the options the table is used with, such as
+// its TableName. Tables which aren't registered are left alone.
+func (r *tableRegistry) replace(old, neu TableChanger) {
+	r.Lock()
+	defer r.Unlock()
+	for i, table := range r.tables {
+		if table == old {
+			r.tables[i] = neu
+			return
+		}
+	}
+}
+
+// registeredTable is implemented by the tables of the keyspaces which record
+// their tables in a registry
+type registeredTable interface {
+	registry() *tableRegistry
+}
+
+// reregister replaces a table, or the recipe of a table, by the result of its
+// WithOptions in the registry of its keyspace
+func reregister(old, neu TableChanger) {
+	var table interface{} = old
+	if recipe, ok := old.(interface{ Table() Table }); ok {
+		table = recipe.Table()
+	}
+	if t, ok := table.(registeredTable); ok && t.registry() != nil {
+		t.registry().replace(old, neu)
+	}
+}
+
+func (r *tableRegistry) rename(legacy, name string) {
+	r.Lock()
+	defer r.Unlock()
+	if r.renamed == nil {
+		r.renamed = map[string]string{}
+	}
+	r.renamed[legacy] = name
+}
+
+func (r *tableRegistry) registered() []TableChanger {
+	r.Lock()
+	defer r.Unlock()
+	return append([]TableChanger{}, r.tables...)
 }
 
 // Connect to a certain keyspace directly. Same as using Connect().KeySpace(keySpaceName)
@@ -30,8 +103,28 @@ func (k *k) DebugMode(b bool) {
 	k.debugMode = b
 }
 
+func (k *k) SetTableNaming(naming TableNaming) {
+	k.naming = naming
+}
+
+func (k *k) TableNameMapping() map[string]string {
+	k.registry.Lock()
+	defer k.registry.Unlock()
+	result := make(map[string]string, len(k.registry.renamed))
+	for legacy, name := range k.registry.renamed {
+		result[legacy] = name
+	}
+	return result
+}
+
 func (k *k) Table(name string, entity interface{}, keys Keys) Table {
-	n := name + "__" + strings.Join(keys.PartitionKeys, "_") + "__" + strings.Join(keys.ClusteringColumns, "_")
+	tbl := k.table(name, entity, keys)
+	k.registry.register(tbl)
+	return tbl
+}
+
+func (k *k) table(name string, entity interface{}, keys Keys) Table {
+	n := k.tableName("%s__%s__%s", name, strings.Join(keys.PartitionKeys, "_"), strings.Join(keys.ClusteringColumns, "_"))
 	m, ok := toMap(entity)
 	if !ok {
 		panic("Unrecognized row type")
@@ -39,6 +132,19 @@ func (k *k) Table(name string, entity interface{}, keys Keys) Table {
 	return k.NewTable(n, entity, m, keys)
 }
 
+func (k *k) TableFromTags(name string, entity interface{}) (Table, error) {
+	keys, options, err := KeysFromTags(entity)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := toMap(entity); !ok {
+		return nil, fmt.Errorf("unrecognized row type %T", entity)
+	}
+	tbl := k.table(name, entity, keys).WithOptions(options)
+	k.registry.register(tbl)
+	return tbl, nil
+}
+
 func (k *k) NewTable(name string, entity interface{}, fields map[string]interface{}, keys Keys) Table {
 	// Act both as a proxy to a tableFactory, and as the tableFactory itself (in most situations, a k will be its own
 	// tableFactory, but not always [ie. mocking])
@@ -54,17 +160,34 @@ func (k *k) NewTable(name string, entity interface{}, fields map[string]interfac
 	}
 }
 
+// columnTyper is implemented by the tables whose column types can be
+// overridden by the recipes
+type columnTyper interface {
+	withColumnType(field, cqlType string) Table
+}
+
+// withColumnType overrides the CQL type of a column of the table of a recipe,
+// for columns whose type can't be derived from their Go type
+func withColumnType(tbl Table, field, cqlType string) Table {
+	if ct, ok := tbl.(columnTyper); ok {
+		return ct.withColumnType(field, cqlType)
+	}
+	return tbl
+}
+
 func (k *k) MapTable(name, id string, row interface{}) MapTable {
 	m, ok := toMap(row)
 	if !ok {
 		panic("Unrecognized row type")
 	}
-	return &mapT{
-		t: k.NewTable(fmt.Sprintf("%s_map_%s", name, id), row, m, Keys{
+	tbl := &mapT{
+		t: k.NewTable(k.tableName("%s_map_%s", name, id), row, m, Keys{
 			PartitionKeys: []string{id},
 		}),
 		idField: id,
 	}
+	k.registry.register(tbl)
+	return tbl
 }
 
 func (k *k) SetKeysSpaceName(name string) {
@@ -76,14 +199,16 @@ func (k *k) MultimapTable(name, fieldToIndexBy, id string, row interface{}) Mult
 	if !ok {
 		panic("Unrecognized row type")
 	}
-	return &multimapT{
-		t: k.NewTable(fmt.Sprintf("%s_multimap_%s_%s", name, fieldToIndexBy, id), row, m, Keys{
+	tbl := &multimapT{
+		t: k.NewTable(k.tableName("%s_multimap_%s_%s", name, fieldToIndexBy, id), row, m, Keys{
 			PartitionKeys:     []string{fieldToIndexBy},
 			ClusteringColumns: []string{id},
 		}),
 		idField:        id,
 		fieldToIndexBy: fieldToIndexBy,
 	}
+	k.registry.register(tbl)
+	return tbl
 }
 
 func (k *k) MultimapMultiKeyTable(name string, fieldToIndexBy, id []string, row interface{}) MultimapMkTable {
@@ -91,14 +216,16 @@ func (k *k) MultimapMultiKeyTable(name string, fieldToIndexBy, id []string, row
 	if !ok {
 		panic("Unrecognized row type")
 	}
-	return &multimapMkT{
-		t: k.NewTable(fmt.Sprintf("%s_multimapMk", name), row, m, Keys{
+	tbl := &multimapMkT{
+		t: k.NewTable(k.tableName("%s_multimapMk", name), row, m, Keys{
 			PartitionKeys:     fieldToIndexBy,
 			ClusteringColumns: id,
 		}),
 		idField:         id,
 		fieldsToIndexBy: fieldToIndexBy,
 	}
+	k.registry.register(tbl)
+	return tbl
 }
 
 func (k *k) TimeSeriesTable(name, timeField, idField string, bucketSize time.Duration, row interface{}) TimeSeriesTable {
@@ -107,8 +234,8 @@ func (k *k) TimeSeriesTable(name, timeField, idField string, bucketSize time.Dur
 		panic("Unrecognized row type")
 	}
 	m[bucketFieldName] = time.Now()
-	return &timeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_timeSeries_%s_%s_%s", name, timeField, idField, bucketSize), row, m, Keys{
+	tbl := &timeSeriesT{
+		t: k.NewTable(k.tableName("%s_timeSeries_%s_%s_%s", name, timeField, idField, bucketSize), row, m, Keys{
 			PartitionKeys:     []string{bucketFieldName},
 			ClusteringColumns: []string{timeField, idField},
 		}),
@@ -116,6 +243,33 @@ func (k *k) TimeSeriesTable(name, timeField, idField string, bucketSize time.Dur
 		idField:    idField,
 		bucketSize: bucketSize,
 	}
+	k.registry.register(tbl)
+	return tbl
+}
+
+func (k *k) ShardedTimeSeriesTable(name, timeField, idField string, shards int, bucketSize time.Duration, row interface{}) TimeSeriesTable {
+	if shards < 1 {
+		panic("A sharded time series needs at least one shard")
+	}
+	m, ok := toMap(row)
+	if !ok {
+		panic("Unrecognized row type")
+	}
+	m[bucketFieldName] = time.Now()
+	m[shardFieldName] = 0
+	tbl := &shardedTimeSeriesT{
+		t: k.NewTable(k.tableName("%s_shardedTimeSeries_%s_%s_%d_%s", name, timeField, idField, shards, bucketSize), row, m, Keys{
+			PartitionKeys:     []string{bucketFieldName, shardFieldName},
+			ClusteringColumns: []string{timeField, idField},
+		}),
+		timeField:  timeField,
+		idField:    idField,
+		idType:     cassaType(m[idField]),
+		shards:     shards,
+		bucketSize: bucketSize,
+	}
+	k.registry.register(tbl)
+	return tbl
 }
 
 func (k *k) MultiTimeSeriesTable(name, indexField, timeField, idField string, bucketSize time.Duration, row interface{}) MultiTimeSeriesTable {
@@ -124,8 +278,8 @@ func (k *k) MultiTimeSeriesTable(name, indexField, timeField, idField string, bu
 		panic("Unrecognized row type")
 	}
 	m[bucketFieldName] = time.Now()
-	return &multiTimeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_multiTimeSeries_%s_%s_%s_%s", name, indexField, timeField, idField, bucketSize.String()), row, m, Keys{
+	tbl := &multiTimeSeriesT{
+		t: k.NewTable(k.tableName("%s_multiTimeSeries_%s_%s_%s_%s", name, indexField, timeField, idField, bucketSize), row, m, Keys{
 			PartitionKeys:     []string{indexField, bucketFieldName},
 			ClusteringColumns: []string{timeField, idField},
 		}),
@@ -134,6 +288,8 @@ func (k *k) MultiTimeSeriesTable(name, indexField, timeField, idField string, bu
 		idField:    idField,
 		bucketSize: bucketSize,
 	}
+	k.registry.register(tbl)
+	return tbl
 }
 
 func (k *k) MultiKeyTimeSeriesTable(name string, indexFields []string, timeField string, idFields []string, bucketSize time.Duration, row interface{}) MultiKeyTimeSeriesTable {
@@ -148,8 +304,8 @@ func (k *k) MultiKeyTimeSeriesTable(name string, indexFields []string, timeField
 	clusteringColumns = append(clusteringColumns, idFields...)
 
 	m[bucketFieldName] = time.Now()
-	return &multiKeyTimeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_multiKeyTimeSeries_%s_%s", name, timeField, bucketSize.String()), row, m, Keys{
+	tbl := &multiKeyTimeSeriesT{
+		t: k.NewTable(k.tableName("%s_multiKeyTimeSeries_%s_%s", name, timeField, bucketSize), row, m, Keys{
 			PartitionKeys:     partitionKeys,
 			ClusteringColumns: clusteringColumns,
 		}),
@@ -158,6 +314,8 @@ func (k *k) MultiKeyTimeSeriesTable(name string, indexFields []string, timeField
 		idFields:    idFields,
 		bucketSize:  bucketSize,
 	}
+	k.registry.register(tbl)
+	return tbl
 }
 
 func (k *k) FlakeSeriesTable(name, idField string, bucketSize time.Duration, row interface{}) FlakeSeriesTable {
@@ -167,14 +325,16 @@ func (k *k) FlakeSeriesTable(name, idField string, bucketSize time.Duration, row
 	}
 	m[flakeTimestampFieldName] = time.Now()
 	m[bucketFieldName] = time.Now()
-	return &flakeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_flakeSeries_%s_%s", name, idField, bucketSize.String()), row, m, Keys{
+	tbl := &flakeSeriesT{
+		t: k.NewTable(k.tableName("%s_flakeSeries_%s_%s", name, idField, bucketSize), row, m, Keys{
 			PartitionKeys:     []string{bucketFieldName},
 			ClusteringColumns: []string{flakeTimestampFieldName, idField},
 		}),
 		idField:    idField,
 		bucketSize: bucketSize,
 	}
+	k.registry.register(tbl)
+	return tbl
 }
 
 func (k *k) MultiFlakeSeriesTable(name, indexField, idField string, bucketSize time.Duration, row interface{}) MultiFlakeSeriesTable {
@@ -184,8 +344,8 @@ func (k *k) MultiFlakeSeriesTable(name, indexField, idField string, bucketSize t
 	}
 	m[flakeTimestampFieldName] = time.Now()
 	m[bucketFieldName] = time.Now()
-	return &multiFlakeSeriesT{
-		t: k.NewTable(fmt.Sprintf("%s_multiflakeSeries_%s_%s_%s", name, indexField, idField, bucketSize.String()), row, m, Keys{
+	tbl := &multiFlakeSeriesT{
+		t: k.NewTable(k.tableName("%s_multiflakeSeries_%s_%s_%s", name, indexField, idField, bucketSize), row, m, Keys{
 			PartitionKeys:     []string{indexField, bucketFieldName},
 			ClusteringColumns: []string{flakeTimestampFieldName, idField},
 		}),
@@ -193,6 +353,73 @@ func (k *k) MultiFlakeSeriesTable(name, indexField, idField string, bucketSize t
 		bucketSize: bucketSize,
 		indexField: indexField,
 	}
+	k.registry.register(tbl)
+	return tbl
+}
+
+func (k *k) MultiKeyFlakeSeriesTable(name string, indexFields []string, idField string, bucketSize time.Duration, row interface{}) MultiKeyFlakeSeriesTable {
+	m, ok := toMap(row)
+	if !ok {
+		panic("Unrecognized row type")
+	}
+
+	partitionKeys := append([]string{}, indexFields...)
+	partitionKeys = append(partitionKeys, bucketFieldName)
+
+	m[flakeTimestampFieldName] = time.Now()
+	m[bucketFieldName] = time.Now()
+	tbl := &multiKeyFlakeSeriesT{
+		t: k.NewTable(k.tableName("%s_multiKeyFlakeSeries_%s_%s", name, idField, bucketSize), row, m, Keys{
+			PartitionKeys:     partitionKeys,
+			ClusteringColumns: []string{flakeTimestampFieldName, idField},
+		}),
+		indexFields: indexFields,
+		idField:     idField,
+		bucketSize:  bucketSize,
+	}
+	k.registry.register(tbl)
+	return tbl
+}
+
+func (k *k) TimeUUIDSeriesTable(name, idField string, bucketSize time.Duration, row interface{}) TimeUUIDSeriesTable {
+	m, ok := toMap(row)
+	if !ok {
+		panic("Unrecognized row type")
+	}
+	m[bucketFieldName] = time.Now()
+	tbl := &timeUUIDSeriesT{
+		t: withColumnType(k.NewTable(k.tableName("%s_timeUUIDSeries_%s_%s", name, idField, bucketSize), row, m, Keys{
+			PartitionKeys:     []string{bucketFieldName},
+			ClusteringColumns: []string{idField},
+		}), idField, "timeuuid"),
+		idField:    idField,
+		bucketSize: bucketSize,
+	}
+	k.registry.register(tbl)
+	return tbl
+}
+
+func (k *k) MultiKeyTimeUUIDSeriesTable(name string, indexFields []string, idField string, bucketSize time.Duration, row interface{}) MultiKeyTimeUUIDSeriesTable {
+	m, ok := toMap(row)
+	if !ok {
+		panic("Unrecognized row type")
+	}
+
+	partitionKeys := append([]string{}, indexFields...)
+	partitionKeys = append(partitionKeys, bucketFieldName)
+
+	m[bucketFieldName] = time.Now()
+	tbl := &multiKeyTimeUUIDSeriesT{
+		t: withColumnType(k.NewTable(k.tableName("%s_multiKeyTimeUUIDSeries_%s_%s", name, idField, bucketSize), row, m, Keys{
+			PartitionKeys:     partitionKeys,
+			ClusteringColumns: []string{idField},
+		}), idField, "timeuuid"),
+		indexFields: indexFields,
+		idField:     idField,
+		bucketSize:  bucketSize,
+	}
+	k.registry.register(tbl)
+	return tbl
 }
 
 type tableInfoMarshal struct {
@@ -238,11 +465,19 @@ func (k *k) Exists(cf string) (bool, error) {
 }
 
 func (k *k) DropTable(cf string) error {
-	query := fmt.Sprintf("DROP TABLE IF EXISTS %s.%s", k.name, cf)
+	return k.dropTable(cf, false)
+}
+
+func (k *k) dropTable(cf string, caseSensitive bool) error {
+	query := fmt.Sprintf("DROP TABLE IF EXISTS %s", tableIdentifier(k.name, cf, caseSensitive))
 	stmt := cqlStatement{query: query}
 	return k.qe.Execute(stmt)
 }
 
+func (k *k) RegisteredTables() []TableChanger {
+	return k.registry.registered()
+}
+
 func (k *k) Name() string {
 	return k.name
 }
@@ -0,0 +1,72 @@
+package gocassa
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisteredTables(t *testing.T) {
+	for _, ks := range []KeySpace{NewConnection(nil).KeySpace("ks1"), NewMockKeySpace()} {
+		ks.MapTable("sale", "Id", typedSale{})
+		ks.MultimapTable("sale", "SellerId", "Id", typedSale{})
+		ks.TimeSeriesTable("sale", "Created", "Id", time.Hour, typedSale{})
+		ks.MapTable("sale", "Id", typedSale{})
+		// The table is registered with the options it is used with
+		tbl := ks.FlakeSeriesTable("sale", "Id", time.Hour, typedSale{}).WithOptions(Options{TableName: "sale_flakes"})
+		// Options of a single use of a table don't replace it
+		tbl.Table().WithOptions(Options{TableName: "sale_once"})
+		_, err := ks.TableFromTags("event", taggedEvent{})
+		require.NoError(t, err)
+
+		var names []string
+		for _, tbl := range ks.RegisteredTables() {
+			names = append(names, tbl.Name())
+		}
+		assert.Equal(t, []string{
+			"sale_map_Id",
+			"sale_multimap_SellerId_Id",
+			"sale_timeSeries_Created_Id_1h0m0s",
+			"sale_flakes",
+			"event__user_id_region__created_event_id",
+		}, names)
+	}
+}
+
+func TestSchemaScript(t *testing.T) {
+	ks := NewConnection(nil).KeySpace("ks1")
+	ks.MapTable("customer", "Id", customerWithAddress{})
+	_, err := ks.TableFromTags("event", taggedEvent{})
+	require.NoError(t, err)
+
+	script, err := SchemaScript(ks, "{'class': 'NetworkTopologyStrategy', 'dc1': 3}")
+	require.NoError(t, err)
+	stmts := strings.Split(strings.TrimSpace(script), "\n\n")
+	require.Len(t, stmts, 5)
+	assert.Equal(t, "CREATE KEYSPACE IF NOT EXISTS ks1 WITH replication = {'class': 'NetworkTopologyStrategy', 'dc1': 3};", stmts[0])
+	assert.True(t, strings.HasPrefix(stmts[1], "CREATE TYPE IF NOT EXISTS ks1.geopoint"))
+	assert.True(t, strings.HasPrefix(stmts[2], "CREATE TYPE IF NOT EXISTS ks1.postaladdress"))
+	assert.True(t, strings.HasPrefix(stmts[3], "CREATE TABLE IF NOT EXISTS ks1.customer_map_Id"))
+	assert.True(t, strings.HasPrefix(stmts[4], "CREATE TABLE IF NOT EXISTS ks1.event__user_id_region__created_event_id"))
+	assert.Contains(t, stmts[4], "WITH CLUSTERING ORDER BY (created DESC, event_id ASC)")
+}
+
+func TestSchemaScriptWithOptions(t *testing.T) {
+	ks := NewConnection(nil).KeySpace("ks1")
+	ks.MapTable("customer", "Id", customerWithAddress{}).WithOptions(Options{TableName: "customers"})
+	tbl := ks.Table("event", taggedEvent{}, Keys{PartitionKeys: []string{"user_id"}, ClusteringColumns: []string{"event_id"}}).
+		WithOptions(Options{TableName: "events"}).
+		WithOptions(Options{ClusteringOrder: []ClusteringOrderColumn{{Direction: DESC, Column: "event_id"}}})
+	assert.Equal(t, "events", tbl.Name())
+
+	script, err := SchemaScript(ks, "")
+	require.NoError(t, err)
+	stmts := strings.Split(strings.TrimSpace(script), "\n\n")
+	require.Len(t, stmts, 5)
+	assert.True(t, strings.HasPrefix(stmts[3], "CREATE TABLE IF NOT EXISTS ks1.customers ("), stmts[3])
+	assert.True(t, strings.HasPrefix(stmts[4], "CREATE TABLE IF NOT EXISTS ks1.events ("), stmts[4])
+	assert.Contains(t, stmts[4], "WITH CLUSTERING ORDER BY (event_id DESC)")
+}
@@ -14,6 +14,9 @@ func (m *mapT) CreateStatement() (Statement, error) { return m.Table().CreateSta
 func (m *mapT) CreateIfNotExistStatement() (Statement, error) {
 	return m.Table().CreateIfNotExistStatement()
 }
+func (m *mapT) CreateTypeStatements() ([]Statement, error) {
+	return m.Table().CreateTypeStatements()
+}
 
 func (m *mapT) Update(id interface{}, ma map[string]interface{}) Op {
 	return m.Table().
@@ -39,14 +42,14 @@ func (m *mapT) Read(id, pointer interface{}) Op {
 }
 
 func (m *mapT) MultiRead(ids []interface{}, pointerToASlice interface{}) Op {
-	return m.Table().
-		Where(In(m.idField, ids...)).
-		Read(pointerToASlice)
+	return newMultiReadOp(m.Table(), nil, m.idField, ids, pointerToASlice)
 }
 
 func (m *mapT) WithOptions(o Options) MapTable {
-	return &mapT{
+	result := &mapT{
 		t:       m.Table().WithOptions(o),
 		idField: m.idField,
 	}
+	reregister(m, result)
+	return result
 }
@@ -3,6 +3,8 @@ package gocassa
 import (
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMapTable(t *testing.T) {
@@ -102,3 +104,22 @@ func TestMapTableMultiRead(t *testing.T) {
 		t.Fatalf("Expected to find jane, got %v", (*customers)[1])
 	}
 }
+
+func TestMapTableMultiReadStatements(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").MapTable("customer", "Id", Customer{})
+	customers := []Customer{}
+	ids := []interface{}{"1", "2", "3"}
+
+	stmt := tbl.MultiRead(ids, &customers).GenerateStatement()
+	assert.Equal(t, "SELECT id, name FROM ks1.customer_map_Id WHERE id IN ?", stmt.Query())
+
+	// With ParallelMultiReadQuery the statement is the one of the first chunk
+	op := tbl.MultiRead(ids, &customers).WithOptions(Options{MultiReadQuery: ParallelMultiReadQuery})
+	stmt = op.GenerateStatement()
+	assert.Equal(t, "SELECT id, name FROM ks1.customer_map_Id WHERE id = ?", stmt.Query())
+	assert.Equal(t, []interface{}{"1"}, stmt.Values())
+
+	stmt = op.WithOptions(Options{MultiReadChunkSize: 2}).GenerateStatement()
+	assert.Equal(t, "SELECT id, name FROM ks1.customer_map_Id WHERE id IN ?", stmt.Query())
+	assert.Equal(t, []interface{}{[]interface{}{"1", "2"}}, stmt.Values())
+}
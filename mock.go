@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 
@@ -165,14 +166,17 @@ func (mo mockMultiOp) Preflight() error {
 
 func (ks *mockKeySpace) NewTable(name string, entity interface{}, fieldSource map[string]interface{}, keys Keys) Table {
 	mt := &MockTable{
-		RWMutex:     &sync.RWMutex{},
-		ksName:      ks.Name(),
-		tableName:   name,
-		entity:      entity,
-		keys:        keys,
-		fieldSource: fieldSource,
-		rows:        map[rowKey]*btree.BTree{},
-		mtx:         &sync.RWMutex{},
+		RWMutex:      &sync.RWMutex{},
+		ksName:       ks.Name(),
+		tableName:    name,
+		entity:       entity,
+		keys:         keys,
+		fieldSource:  fieldSource,
+		fieldOptions: fieldColumnOptions(entity),
+		tables:       &ks.registry,
+		rows:         map[rowKey]*btree.BTree{},
+		statics:      map[rowKey]map[string]interface{}{},
+		mtx:          &sync.RWMutex{},
 	}
 
 	fields := []string{}
@@ -195,15 +199,20 @@ type MockTable struct {
 	*sync.RWMutex
 
 	// rows is mapping from row key to column group key to column map
-	mtx         *sync.RWMutex
-	ksName      string
-	tableName   string
-	rows        map[rowKey]*btree.BTree
-	entity      interface{}
-	fieldSource map[string]interface{}
-	fields      []string
-	keys        Keys
-	options     Options
+	mtx       *sync.RWMutex
+	ksName    string
+	tableName string
+	rows      map[rowKey]*btree.BTree
+	// statics is mapping from row key to the static columns of the
+	// partition, including the partition key columns
+	statics      map[rowKey]map[string]interface{}
+	entity       interface{}
+	fieldSource  map[string]interface{}
+	fieldOptions map[string]columnOptions
+	fields       []string
+	keys         Keys
+	options      Options
+	tables       *tableRegistry // registry of the keyspace of the table
 }
 
 type rowKey string
@@ -259,11 +268,22 @@ func (k *keyPart) Bytes() []byte {
 	return marshalled
 }
 
+// Compare orders the key parts by their marshalled value, apart from time
+// UUIDs which are ordered by time like Cassandra does
+func (k *keyPart) Compare(other *keyPart) int {
+	if a, ok := k.Value.(gocql.UUID); ok {
+		if b, ok := other.Value.(gocql.UUID); ok {
+			return compareUUIDs(a, b)
+		}
+	}
+	return bytes.Compare(k.Bytes(), other.Bytes())
+}
+
 type key []keyPart
 
 func (k key) Less(other key) bool {
 	for i := 0; i < len(k) && i < len(other); i++ {
-		cmp := bytes.Compare(k[i].Bytes(), other[i].Bytes())
+		cmp := k[i].Compare(&other[i])
 		if cmp == 0 {
 			continue
 		}
@@ -345,6 +365,19 @@ func (t *MockTable) Name() string {
 	return t.tableName
 }
 
+// normaliseSets returns a copy of the columns where the values of set columns
+// have been deduplicated and sorted, as Cassandra stores them
+func (t *MockTable) normaliseSets(columns map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(columns))
+	for k, v := range columns {
+		if rv := reflect.ValueOf(v); t.fieldOptions[k].set && rv.Kind() == reflect.Slice {
+			v = sortedSet(rv).Interface()
+		}
+		result[k] = v
+	}
+	return result
+}
+
 func (t *MockTable) getOrCreateRow(rowKey key) *btree.BTree {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
@@ -356,6 +389,64 @@ func (t *MockTable) getOrCreateRow(rowKey key) *btree.BTree {
 	return row
 }
 
+// isStatic returns whether the column is shared by all rows of a partition
+func (t *MockTable) isStatic(column string) bool {
+	return len(t.keys.ClusteringColumns) > 0 && t.fieldOptions[column].static
+}
+
+// splitStatics separates the static columns from the regular ones
+func (t *MockTable) splitStatics(columns map[string]interface{}) (statics, regular map[string]interface{}) {
+	statics, regular = map[string]interface{}{}, map[string]interface{}{}
+	for k, v := range columns {
+		if t.isStatic(k) {
+			statics[k] = v
+		} else {
+			regular[k] = v
+		}
+	}
+	return statics, regular
+}
+
+func (t *MockTable) getOrCreateStatics(rowKey key) map[string]interface{} {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	statics := t.statics[rowKey.RowKey()]
+	if statics == nil {
+		statics = map[string]interface{}{}
+		for _, keyPart := range rowKey {
+			statics[keyPart.Key] = keyPart.Value
+		}
+		t.statics[rowKey.RowKey()] = statics
+	}
+	return statics
+}
+
+// withStatics returns the columns of a row merged with the static columns of
+// its partition
+func (t *MockTable) withStatics(rk rowKey, columns map[string]interface{}) map[string]interface{} {
+	statics := t.statics[rk]
+	if len(statics) == 0 {
+		return columns
+	}
+	result := make(map[string]interface{}, len(columns)+len(statics))
+	for k, v := range statics {
+		result[k] = v
+	}
+	for k, v := range columns {
+		result[k] = v
+	}
+	return result
+}
+
+// staticOnlyRow returns the static columns of a partition which has no
+// rows, as Cassandra still returns those when the partition is read
+func (t *MockTable) staticOnlyRow(rk rowKey) map[string]interface{} {
+	if row := t.rows[rk]; row != nil && row.Len() > 0 {
+		return nil
+	}
+	return t.statics[rk]
+}
+
 func (t *MockTable) getOrCreateColumnGroup(rowKey, superColumnKey key) map[string]interface{} {
 	row := t.getOrCreateRow(rowKey)
 	scol := superColumnKey.ToSuperColumn()
@@ -389,9 +480,16 @@ func (t *MockTable) SetWithOptions(i interface{}, options Options) Op {
 			return err
 		}
 
+		statics, regular := t.splitStatics(t.normaliseSets(columns))
+		if len(statics) > 0 {
+			if err := assignRecords(statics, t.getOrCreateStatics(rowKey)); err != nil {
+				return err
+			}
+		}
+
 		superColumn := t.getOrCreateColumnGroup(rowKey, superColumnKey)
 
-		if err := assignRecords(columns, superColumn); err != nil {
+		if err := assignRecords(regular, superColumn); err != nil {
 			return err
 		}
 		return nil
@@ -409,39 +507,102 @@ func (t *MockTable) Where(relations ...Relation) Filter {
 	}
 }
 
+func (t *MockTable) DistinctPartitionKeys(out interface{}) Op {
+	return newOp(func(m mockOp) error {
+		t.Lock()
+		defer t.Unlock()
+
+		t.mtx.RLock()
+		partitions := map[rowKey]map[string]interface{}{}
+		for rk, statics := range t.statics {
+			partitions[rk] = statics
+		}
+		for rk, row := range t.rows {
+			if row.Len() > 0 {
+				partitions[rk] = row.Min().(*superColumn).Columns
+			}
+		}
+		t.mtx.RUnlock()
+
+		rowKeys := make([]string, 0, len(partitions))
+		for rk := range partitions {
+			rowKeys = append(rowKeys, string(rk))
+		}
+		sort.Strings(rowKeys)
+
+		result := make([]map[string]interface{}, 0, len(rowKeys))
+		for _, rk := range rowKeys {
+			columns := partitions[rowKey(rk)]
+			keyColumns := make(map[string]interface{}, len(t.keys.PartitionKeys))
+			for _, k := range t.keys.PartitionKeys {
+				keyColumns[k] = columns[k]
+			}
+			result = append(result, keyColumns)
+		}
+
+		stmt := SelectStatement{keyspace: t.ksName, table: t.Name(), fields: t.keys.PartitionKeys}
+		iter := newMockIterator(result, stmt.fields)
+		_, err := NewScanner(stmt, out).ScanIter(iter)
+		return err
+	})
+}
+
 func (t *MockTable) Create() error {
-	return nil
+	return validateTableName(t.Name())
 }
 
 func (t *MockTable) CreateStatement() (Statement, error) {
-	return noOpStatement{}, nil
+	return noOpStatement{}, validateTableName(t.Name())
 }
 
 func (t *MockTable) CreateIfNotExist() error {
-	return nil
+	return validateTableName(t.Name())
 }
 
 func (t *MockTable) CreateIfNotExistStatement() (Statement, error) {
-	return noOpStatement{}, nil
+	return noOpStatement{}, validateTableName(t.Name())
+}
+
+func (t *MockTable) CreateTypeStatements() ([]Statement, error) {
+	return nil, nil
 }
 
 func (t *MockTable) Recreate() error {
-	return nil
+	return validateTableName(t.Name())
+}
+
+func (t *MockTable) Options() Options {
+	return t.options
 }
 
 func (t *MockTable) WithOptions(o Options) Table {
-	return &MockTable{
-		RWMutex:     t.RWMutex,
-		ksName:      t.ksName,
-		tableName:   t.tableName,
-		rows:        t.rows,
-		entity:      t.entity,
-		keys:        t.keys,
-		fieldSource: t.fieldSource,
-		fields:      t.fields,
-		options:     t.options.Merge(o),
-		mtx:         t.mtx,
+	result := &MockTable{
+		RWMutex:      t.RWMutex,
+		ksName:       t.ksName,
+		tableName:    t.tableName,
+		rows:         t.rows,
+		statics:      t.statics,
+		entity:       t.entity,
+		keys:         t.keys,
+		fieldSource:  t.fieldSource,
+		fieldOptions: t.fieldOptions,
+		fields:       t.fields,
+		options:      t.options.Merge(o),
+		mtx:          t.mtx,
+		tables:       t.tables,
 	}
+	reregister(t, result)
+	return result
+}
+
+func (t *MockTable) registry() *tableRegistry {
+	return t.tables
+}
+
+func (t *MockTable) withColumnType(field, cqlType string) Table {
+	result := *t
+	result.fieldOptions = overrideColumnType(t.fieldOptions, field, cqlType)
+	return &result
 }
 
 // MockFilter implements the Filter interface and works with MockTable.
@@ -460,7 +621,14 @@ func (f *MockFilter) Relations() []Relation {
 
 func (f *MockFilter) rowMatch(row map[string]interface{}) bool {
 	for _, relation := range f.relations {
-		value := row[relation.Field()]
+		var value interface{} = row[relation.Field()]
+		if relation.IsTuple() {
+			values := make([]interface{}, len(relation.Fields()))
+			for i, field := range relation.Fields() {
+				values[i] = row[field]
+			}
+			value = values
+		}
 		if !relation.accept(value) {
 			return false
 		}
@@ -468,11 +636,29 @@ func (f *MockFilter) rowMatch(row map[string]interface{}) bool {
 	return true
 }
 
+// matchesStaticOnlyRow returns whether the static columns of a partition with
+// no rows match the filter. Like Cassandra, such partitions are only read when
+// the clustering columns aren't restricted.
+func (f *MockFilter) matchesStaticOnlyRow(statics map[string]interface{}) bool {
+	for _, relation := range f.relations {
+		for _, field := range relation.Fields() {
+			for _, column := range f.table.keys.ClusteringColumns {
+				if field == column {
+					return false
+				}
+			}
+		}
+	}
+	return f.rowMatch(statics)
+}
+
 func (f *MockFilter) fieldRelationMap() map[string]Relation {
 	result := map[string]Relation{}
 
 	for _, relation := range f.relations {
-		result[relation.Field()] = relation
+		if !relation.IsTuple() {
+			result[relation.Field()] = relation
+		}
 	}
 
 	return result
@@ -521,7 +707,18 @@ func (f *MockFilter) UpdateWithOptions(m map[string]interface{}, options Options
 			return err
 		}
 
+		statics, regular := f.table.splitStatics(f.table.normaliseSets(m))
 		for _, rowKey := range rowKeys {
+			if len(statics) > 0 {
+				if err := assignRecords(statics, f.table.getOrCreateStatics(rowKey)); err != nil {
+					return err
+				}
+			}
+			// Static columns can be updated with only the partition key
+			if len(regular) == 0 {
+				continue
+			}
+
 			superColumnKeys, err := f.fieldsFromRelations(f.table.keys.ClusteringColumns)
 			if err != nil {
 				return err
@@ -536,7 +733,7 @@ func (f *MockFilter) UpdateWithOptions(m map[string]interface{}, options Options
 					}
 				}
 
-				if err := assignRecords(m, superColumn); err != nil {
+				if err := assignRecords(regular, superColumn); err != nil {
 					return err
 				}
 			}
@@ -562,47 +759,149 @@ func (f *MockFilter) Delete() Op {
 
 		f.table.mtx.Lock()
 		defer f.table.mtx.Unlock()
+		wholePartition := len(f.relations) == len(f.table.keys.PartitionKeys)
 		for _, rowKey := range rowKeys {
+			if wholePartition {
+				delete(f.table.statics, rowKey.RowKey())
+			}
+
 			row := f.table.rows[rowKey.RowKey()]
 			if row == nil {
-				return nil
+				continue
 			}
 
+			// Items can't be removed from the tree while iterating over it
+			var matched []btree.Item
 			row.Ascend(func(item btree.Item) bool {
 				columns := item.(*superColumn).Columns
 				if f.rowMatch(columns) {
-					row.Delete(item)
+					matched = append(matched, item)
+				}
+
+				return true
+			})
+			for _, item := range matched {
+				row.Delete(item)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (f *MockFilter) DeleteColumns(columns ...DeleteColumn) Op {
+	return newOp(func(m mockOp) error {
+		f.table.Lock()
+		defer f.table.Unlock()
+
+		for _, column := range columns {
+			if isKeyColumn(column.Field(), f.table.keys.PartitionKeys, f.table.keys.ClusteringColumns) {
+				return fmt.Errorf("Invalid deletion of PRIMARY KEY part `%s`", column.Field())
+			}
+		}
+
+		rowKeys, err := f.fieldsFromRelations(f.table.keys.PartitionKeys)
+		if err != nil {
+			return err
+		}
+
+		f.table.mtx.Lock()
+		defer f.table.mtx.Unlock()
+		for _, rowKey := range rowKeys {
+			if statics := f.table.statics[rowKey.RowKey()]; statics != nil {
+				for _, column := range columns {
+					if !f.table.isStatic(column.Field()) {
+						continue
+					}
+					if err := deleteColumn(statics, column); err != nil {
+						return err
+					}
 				}
+			}
 
+			row := f.table.rows[rowKey.RowKey()]
+			if row == nil {
+				continue
+			}
+
+			row.Ascend(func(item btree.Item) bool {
+				record := item.(*superColumn).Columns
+				if !f.rowMatch(record) {
+					return true
+				}
+				for _, column := range columns {
+					if f.table.isStatic(column.Field()) {
+						continue
+					}
+					if err = deleteColumn(record, column); err != nil {
+						return false
+					}
+				}
 				return true
 			})
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
 	})
 }
 
+// deleteColumn deletes a column, or an element of a map or list column, from
+// the record. The collections are copied rather than modified in place, as
+// they may be shared with the values which were written
+func deleteColumn(record map[string]interface{}, column DeleteColumn) error {
+	element, ok := column.Element()
+	if !ok {
+		delete(record, column.Field())
+		return nil
+	}
+
+	value := record[column.Field()]
+	if value == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		result := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		for _, k := range rv.MapKeys() {
+			if !anyEquals(k.Interface(), toI(element)) {
+				result.SetMapIndex(k, rv.MapIndex(k))
+			}
+		}
+		record[column.Field()] = result.Interface()
+	case reflect.Slice:
+		index, ok := element.(int)
+		if !ok {
+			return fmt.Errorf("Invalid list index %v for field %v", element, column.Field())
+		}
+		if index < 0 || index >= rv.Len() {
+			return fmt.Errorf("List index %v out of bound, list has size %v", index, rv.Len())
+		}
+		result := reflect.MakeSlice(rv.Type(), 0, rv.Len()-1)
+		result = reflect.AppendSlice(result, rv.Slice(0, index))
+		result = reflect.AppendSlice(result, rv.Slice(index+1, rv.Len()))
+		record[column.Field()] = result.Interface()
+	default:
+		return fmt.Errorf("Can't delete an element of field %v that isn't a map or a list: %T", column.Field(), value)
+	}
+	return nil
+}
+
 func (q *MockFilter) Read(out interface{}) Op {
 	return newOp(func(m mockOp) error {
 		q.table.Lock()
 		defer q.table.Unlock()
 
-		var (
-			result []map[string]interface{}
-			err    error
-		)
-
-		switch {
-		case len(q.Relations()) == 0:
-			result = q.readAllRows()
-		default:
-			result, err = q.readSomeRows()
-		}
+		result, err := q.readRows()
 		if err != nil {
 			return err
 		}
 
 		opt := q.table.options.Merge(m.options)
+		result = q.table.orderRows(result, opt.ClusteringOrder)
 		if opt.Limit > 0 && opt.Limit < len(result) {
 			result = result[:opt.Limit]
 		}
@@ -619,6 +918,152 @@ func (q *MockFilter) Read(out interface{}) Op {
 	})
 }
 
+func (q *MockFilter) Count(count *int64) Op {
+	return q.Aggregate(AggregateCount, "*", count)
+}
+
+func (q *MockFilter) Aggregate(fn AggregateFunc, field string, out interface{}) Op {
+	return newOp(func(m mockOp) error {
+		q.table.Lock()
+		defer q.table.Unlock()
+
+		rows, err := q.readRows()
+		if err != nil {
+			return err
+		}
+
+		result, err := aggregateRows(fn, field, rows)
+		if err != nil {
+			return err
+		}
+
+		rv := reflect.ValueOf(out)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return fmt.Errorf("can only decode a single value into a pointer, not %T", out)
+		}
+		if result == nil {
+			rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+			return nil
+		}
+		value := reflect.ValueOf(result)
+		if !value.Type().ConvertibleTo(rv.Elem().Type()) {
+			return fmt.Errorf("can't decode %T into %T", result, out)
+		}
+		rv.Elem().Set(value.Convert(rv.Elem().Type()))
+		return nil
+	})
+}
+
+// aggregateRows applies the aggregate function to a field of the rows. The
+// result is nil if there are no (non null) values, as in Cassandra
+func aggregateRows(fn AggregateFunc, field string, rows []map[string]interface{}) (interface{}, error) {
+	values := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if field == "*" {
+			values = append(values, row)
+		} else if value, ok := row[field]; ok && value != nil {
+			values = append(values, value)
+		}
+	}
+
+	if fn == AggregateCount {
+		return int64(len(values)), nil
+	}
+	if field == "*" {
+		return nil, fmt.Errorf("Aggregate %v can't be applied to *", fn)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	switch fn {
+	case AggregateMin, AggregateMax:
+		result := values[0]
+		for _, value := range values[1:] {
+			a, b := convertToPrimitive(value), convertToPrimitive(result)
+			var better bool
+			var err error
+			if fn == AggregateMin {
+				better, err = builtinLessThan(a, b)
+			} else {
+				better, err = builtinGreaterThan(a, b)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if better {
+				result = value
+			}
+		}
+		return result, nil
+	case AggregateSum, AggregateAvg:
+		var intSum int64
+		var floatSum float64
+		isFloat := false
+		for _, value := range values {
+			rv := reflect.ValueOf(value)
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				intSum += rv.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				intSum += int64(rv.Uint())
+			case reflect.Float32, reflect.Float64:
+				isFloat = true
+				floatSum += rv.Float()
+			default:
+				return nil, fmt.Errorf("Aggregate %v of %T not supported by mock keyspace", fn, value)
+			}
+		}
+		// Like in Cassandra, the average of integers is an integer
+		if isFloat {
+			floatSum += float64(intSum)
+			if fn == AggregateAvg {
+				return floatSum / float64(len(values)), nil
+			}
+			return floatSum, nil
+		}
+		if fn == AggregateAvg {
+			return intSum / int64(len(values)), nil
+		}
+		return intSum, nil
+	}
+	return nil, fmt.Errorf("Aggregate %v not supported by mock keyspace", fn)
+}
+
+// readRows returns the rows matching the filter
+func (q *MockFilter) readRows() ([]map[string]interface{}, error) {
+	if len(q.Relations()) == 0 {
+		return q.readAllRows(), nil
+	}
+	return q.readSomeRows()
+}
+
+// orderRows reverses the rows of every partition if the first column of the
+// order is descending. Rows are stored in ascending order, so this returns
+// them in the order of ORDER BY.
+func (t *MockTable) orderRows(rows []map[string]interface{}, order []ClusteringOrderColumn) []map[string]interface{} {
+	if len(order) == 0 || order[0].Direction != DESC {
+		return rows
+	}
+
+	result := make([]map[string]interface{}, 0, len(rows))
+	for start := 0; start < len(rows); {
+		partition, _ := t.partitionKeyFromColumnValues(rows[start], t.keys.PartitionKeys)
+		end := start + 1
+		for ; end < len(rows); end++ {
+			next, _ := t.partitionKeyFromColumnValues(rows[end], t.keys.PartitionKeys)
+			if next.RowKey() != partition.RowKey() {
+				break
+			}
+		}
+		for i := end - 1; i >= start; i-- {
+			result = append(result, rows[i])
+		}
+		start = end
+	}
+	return result
+}
+
 func (q *MockFilter) readSomeRows() ([]map[string]interface{}, error) {
 	q.table.mtx.RLock()
 	defer q.table.mtx.RUnlock()
@@ -630,13 +1075,17 @@ func (q *MockFilter) readSomeRows() ([]map[string]interface{}, error) {
 
 	var result []map[string]interface{}
 	for _, rowKey := range rowKeys {
+		if statics := q.table.staticOnlyRow(rowKey.RowKey()); statics != nil && q.matchesStaticOnlyRow(statics) {
+			result = append(result, statics)
+		}
+
 		row := q.table.rows[rowKey.RowKey()]
 		if row == nil {
 			continue
 		}
 
 		row.Ascend(func(item btree.Item) bool {
-			columns := item.(*superColumn).Columns
+			columns := q.table.withStatics(rowKey.RowKey(), item.(*superColumn).Columns)
 			if q.rowMatch(columns) {
 				result = append(result, columns)
 			}
@@ -652,9 +1101,14 @@ func (q *MockFilter) readAllRows() []map[string]interface{} {
 	q.table.mtx.RLock()
 	defer q.table.mtx.RUnlock()
 	var result []map[string]interface{}
-	for _, row := range q.table.rows {
+	for rk := range q.table.statics {
+		if statics := q.table.staticOnlyRow(rk); statics != nil && q.matchesStaticOnlyRow(statics) {
+			result = append(result, statics)
+		}
+	}
+	for rk, row := range q.table.rows {
 		row.Ascend(func(item btree.Item) bool {
-			columns := item.(*superColumn).Columns
+			columns := q.table.withStatics(rk, item.(*superColumn).Columns)
 			if q.rowMatch(columns) {
 				result = append(result, columns)
 			}
@@ -899,6 +1353,60 @@ func assignRecords(m map[string]interface{}, record map[string]interface{}) erro
 				delta := int64(v.args[0].(int))
 
 				record[k] = oldV + delta
+			case ModifierSetAdd, ModifierSetRemove:
+				var targetSet reflect.Value
+				if record[k] != nil {
+					targetSet = reflect.ValueOf(record[k])
+					if targetSet.Kind() != reflect.Slice {
+						return fmt.Errorf("Can't use set modifier on field that isn't a slice: %T", record[k])
+					}
+				} else if len(v.args) > 0 && v.op == ModifierSetAdd {
+					targetSet = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(v.args[0])), 0, len(v.args))
+				} else {
+					continue
+				}
+
+				result := reflect.MakeSlice(targetSet.Type(), 0, targetSet.Len()+len(v.args))
+				for i := 0; i < targetSet.Len(); i++ {
+					elem := targetSet.Index(i)
+					if v.op == ModifierSetRemove && setContains(v.args, elem.Interface()) {
+						continue
+					}
+					result = reflect.Append(result, elem)
+				}
+				if v.op == ModifierSetAdd {
+					elemType := targetSet.Type().Elem()
+					for _, arg := range v.args {
+						elem := reflect.ValueOf(arg)
+						if !elem.Type().ConvertibleTo(elemType) {
+							return fmt.Errorf("Can't add %T to set of %v", arg, elemType)
+						}
+						result = reflect.Append(result, elem.Convert(elemType))
+					}
+				}
+				record[k] = sortedSet(result).Interface()
+			case ModifierMapRemoveKeys:
+				if record[k] == nil {
+					continue
+				}
+				rv := reflect.ValueOf(record[k])
+				if rv.Kind() != reflect.Map {
+					return fmt.Errorf("Can't use MapRemoveKeys modifier on field that isn't a map: %T", record[k])
+				}
+				result := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+				for _, key := range rv.MapKeys() {
+					if !anyEquals(key.Interface(), v.args) {
+						result.SetMapIndex(key, rv.MapIndex(key))
+					}
+				}
+				record[k] = result.Interface()
+			case ModifierListAppend, ModifierListAppendAll, ModifierListPrepend, ModifierListPrependAll,
+				ModifierListSetAtIndex, ModifierListRemove:
+				result, err := modifyList(record[k], v)
+				if err != nil {
+					return err
+				}
+				record[k] = result
 			default:
 				return fmt.Errorf("Modifer %v not supported by mock keyspace", v.op)
 			}
@@ -909,3 +1417,104 @@ func assignRecords(m map[string]interface{}, record map[string]interface{}) erro
 
 	return nil
 }
+
+// modifyList applies a list modifier to a copy of the list
+func modifyList(list interface{}, m Modifier) (interface{}, error) {
+	var values []interface{}
+	switch m.op {
+	case ModifierListSetAtIndex:
+		values = m.args[1:]
+	case ModifierListAppendAll, ModifierListPrependAll:
+		values = m.args
+	default:
+		values = m.args[:1]
+	}
+
+	var target reflect.Value
+	if list != nil {
+		target = reflect.ValueOf(list)
+		if target.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("Can't use list modifier on field that isn't a slice: %T", list)
+		}
+	} else if len(values) > 0 && values[0] != nil {
+		target = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(values[0])), 0, 0)
+	} else {
+		return list, nil
+	}
+
+	elemType := target.Type().Elem()
+	elems := reflect.MakeSlice(target.Type(), 0, len(values))
+	for _, value := range values {
+		elem := reflect.ValueOf(value)
+		// Go converts integers to strings as runes, which isn't what Cassandra does
+		if !elem.IsValid() || !elem.Type().ConvertibleTo(elemType) ||
+			(elemType.Kind() == reflect.String && elem.Kind() != reflect.String) {
+			return nil, fmt.Errorf("Can't use %T as element of list of %v", value, elemType)
+		}
+		elems = reflect.Append(elems, elem.Convert(elemType))
+	}
+
+	result := reflect.MakeSlice(target.Type(), 0, target.Len()+elems.Len())
+	switch m.op {
+	case ModifierListAppend, ModifierListAppendAll:
+		result = reflect.AppendSlice(reflect.AppendSlice(result, target), elems)
+	case ModifierListPrepend, ModifierListPrependAll:
+		result = reflect.AppendSlice(reflect.AppendSlice(result, elems), target)
+	case ModifierListSetAtIndex:
+		index, ok := m.args[0].(int)
+		if !ok || index < 0 || index >= target.Len() {
+			return nil, fmt.Errorf("List index %v out of bound, list has size %v", m.args[0], target.Len())
+		}
+		result = reflect.AppendSlice(result, target)
+		result.Index(index).Set(elems.Index(0))
+	case ModifierListRemove:
+		for i := 0; i < target.Len(); i++ {
+			if !anyEquals(target.Index(i).Interface(), values) {
+				result = reflect.Append(result, target.Index(i))
+			}
+		}
+	}
+	return result.Interface(), nil
+}
+
+// sortedSet returns a copy of the slice with duplicate elements removed and
+// the remaining elements sorted, which is how Cassandra returns sets
+func sortedSet(slice reflect.Value) reflect.Value {
+	elems := make([]interface{}, 0, slice.Len())
+	result := reflect.MakeSlice(slice.Type(), 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		if setContains(elems, elem.Interface()) {
+			continue
+		}
+		elems = append(elems, elem.Interface())
+		result = reflect.Append(result, elem)
+	}
+
+	sort.Sort(sortableSlice{result, reflect.Swapper(result.Interface())})
+	return result
+}
+
+func setContains(elems []interface{}, value interface{}) bool {
+	primVal := convertToPrimitive(value)
+	for _, elem := range elems {
+		if reflect.DeepEqual(primVal, convertToPrimitive(elem)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortableSlice sorts a slice of any element type by comparing the primitive
+// representation of its elements
+type sortableSlice struct {
+	v    reflect.Value
+	swap func(i, j int)
+}
+
+func (s sortableSlice) Len() int      { return s.v.Len() }
+func (s sortableSlice) Swap(i, j int) { s.swap(i, j) }
+func (s sortableSlice) Less(i, j int) bool {
+	less, _ := builtinLessThan(convertToPrimitive(s.v.Index(i).Interface()), convertToPrimitive(s.v.Index(j).Interface()))
+	return less
+}
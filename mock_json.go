@@ -0,0 +1,320 @@
+package gocassa
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"gopkg.in/inf.v0"
+
+	r "github.com/rkilburn/gocassa/reflect"
+)
+
+// cassandraTimestampFormat is the format of timestamps in Cassandra's JSON
+const cassandraTimestampFormat = "2006-01-02 15:04:05.000Z07:00"
+
+func (q *MockFilter) ReadJSON(out *[]json.RawMessage) Op {
+	return newOp(func(m mockOp) error {
+		q.table.Lock()
+		defer q.table.Unlock()
+
+		result, err := q.readRows()
+		if err != nil {
+			return err
+		}
+
+		opt := q.table.options.Merge(m.options)
+		result = q.table.orderRows(result, opt.ClusteringOrder)
+		if opt.Limit > 0 && opt.Limit < len(result) {
+			result = result[:opt.Limit]
+		}
+
+		fieldNames := opt.Select
+		if len(opt.Select) == 0 {
+			fieldNames = q.table.fields
+		}
+
+		rows := make([]map[string]interface{}, len(result))
+		for i, columns := range result {
+			row, err := q.table.rowJSON(columns, fieldNames, opt.CaseSensitive)
+			if err != nil {
+				return err
+			}
+			rows[i] = map[string]interface{}{"[json]": row}
+		}
+
+		iter := newMockIterator(rows, []string{"[json]"})
+		_, err = newJSONScanner(out).ScanIter(iter)
+		return err
+	})
+}
+
+func (t *MockTable) SetJSON(row []byte) Op {
+	return newOp(func(m mockOp) error {
+		opt := t.options.Merge(m.options)
+		columns, err := t.columnsFromJSON(row, opt.CaseSensitive)
+		if err != nil {
+			return err
+		}
+
+		if !opt.DefaultUnset {
+			// Columns missing from the JSON are set to null
+			for _, field := range t.fields {
+				if _, ok := columns[field]; !ok {
+					columns[field] = nil
+				}
+			}
+		}
+		return t.SetWithOptions(columns, opt).Run()
+	})
+}
+
+// rowJSON renders the columns of a row as a JSON object the way Cassandra
+// does for SELECT JSON, with the keys in the order of the fields
+func (t *MockTable) rowJSON(columns map[string]interface{}, fields []string, caseSensitive bool) (string, error) {
+	buf := bytes.Buffer{}
+	buf.WriteString("{")
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		key, _ := json.Marshal(columnName(field, caseSensitive))
+		buf.Write(key)
+		buf.WriteString(": ")
+
+		value, err := cassandraJSON(columns[field], t.fieldOptions[field])
+		if err != nil {
+			return "", err
+		}
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.String(), nil
+}
+
+// cassandraJSON renders a value in the format Cassandra uses for the CQL
+// type of the value
+func cassandraJSON(value interface{}, opts columnOptions) ([]byte, error) {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || ((rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil()) {
+		return []byte("null"), nil
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		if opts.cqlType == "date" {
+			return json.Marshal(v.UTC().Format("2006-01-02"))
+		}
+		return json.Marshal(v.UTC().Format(cassandraTimestampFormat))
+	case []byte:
+		return json.Marshal("0x" + hex.EncodeToString(v))
+	case gocql.UUID:
+		return json.Marshal(v.String())
+	case net.IP:
+		return json.Marshal(v.String())
+	case *big.Int:
+		return []byte(v.String()), nil
+	case *inf.Dec:
+		return []byte(v.String()), nil
+	case gocql.Duration:
+		return json.Marshal(durationString(v))
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		elems := make([][]byte, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			elem, err := cassandraJSON(rv.Index(i).Interface(), columnOptions{})
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = elem
+		}
+		return []byte("[" + string(bytes.Join(elems, []byte(", "))) + "]"), nil
+	case reflect.Map:
+		return mapJSON(rv)
+	case reflect.Struct:
+		return structJSON(rv, opts.tuple)
+	case reflect.String:
+		return json.Marshal(rv.String())
+	}
+	return json.Marshal(value)
+}
+
+// mapJSON renders a map as a JSON object. Keys which aren't rendered as JSON
+// strings are rendered as strings of their JSON, like Cassandra does
+func mapJSON(rv reflect.Value) ([]byte, error) {
+	entries := make([]string, 0, rv.Len())
+	for _, k := range rv.MapKeys() {
+		key, err := cassandraJSON(k.Interface(), columnOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if len(key) == 0 || key[0] != '"' {
+			key, _ = json.Marshal(string(key))
+		}
+		value, err := cassandraJSON(rv.MapIndex(k).Interface(), columnOptions{})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, string(key)+": "+string(value))
+	}
+	// Cassandra returns the entries of maps in key order
+	sort.Strings(entries)
+	return []byte("{" + strings.Join(entries, ", ") + "}"), nil
+}
+
+// structJSON renders a user-defined type as a JSON object, or a tuple as a
+// JSON array
+func structJSON(rv reflect.Value, tuple bool) ([]byte, error) {
+	fields, err := r.StructFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		value, err := cassandraJSON(rv.FieldByIndex(f.Index()).Interface(), columnOptionsOfField(f))
+		if err != nil {
+			return nil, err
+		}
+		if tuple {
+			values[i] = string(value)
+			continue
+		}
+		key, _ := json.Marshal(columnName(f.Name(), false))
+		values[i] = string(key) + ": " + string(value)
+	}
+	if tuple {
+		return []byte("[" + strings.Join(values, ", ") + "]"), nil
+	}
+	return []byte("{" + strings.Join(values, ", ") + "}"), nil
+}
+
+// durationString formats a duration the way Cassandra does, for example
+// "1mo2d3h4m"
+func durationString(d gocql.Duration) string {
+	buf := bytes.Buffer{}
+	if d.Months < 0 || d.Days < 0 || d.Nanoseconds < 0 {
+		buf.WriteString("-")
+		d = gocql.Duration{Months: -d.Months, Days: -d.Days, Nanoseconds: -d.Nanoseconds}
+	}
+	if d.Months != 0 {
+		fmt.Fprintf(&buf, "%dmo", d.Months)
+	}
+	if d.Days != 0 {
+		fmt.Fprintf(&buf, "%dd", d.Days)
+	}
+	nanos := d.Nanoseconds
+	for _, unit := range []struct {
+		name string
+		size int64
+	}{
+		{"h", int64(time.Hour)},
+		{"m", int64(time.Minute)},
+		{"s", int64(time.Second)},
+		{"ms", int64(time.Millisecond)},
+		{"us", int64(time.Microsecond)},
+		{"ns", 1},
+	} {
+		if nanos >= unit.size {
+			fmt.Fprintf(&buf, "%d%s", nanos/unit.size, unit.name)
+			nanos %= unit.size
+		}
+	}
+	if buf.Len() == 0 {
+		return "0s"
+	}
+	return buf.String()
+}
+
+// columnsFromJSON parses a JSON row into the values of the columns of the
+// table, converting the values from the formats used by Cassandra
+func (t *MockTable) columnsFromJSON(row []byte, caseSensitive bool) (map[string]interface{}, error) {
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(row, &values); err != nil {
+		return nil, fmt.Errorf("Could not decode JSON string: %v", err)
+	}
+
+	columns := make(map[string]interface{}, len(values))
+	for name, raw := range values {
+		field, ok := t.fieldOfJSONKey(name, caseSensitive)
+		if !ok {
+			return nil, fmt.Errorf("JSON values map contains unrecognized column: %s", name)
+		}
+		value, err := fromCassandraJSON(raw, reflect.TypeOf(t.fieldSource[field]))
+		if err != nil {
+			return nil, fmt.Errorf("Error decoding JSON value for %s: %v", name, err)
+		}
+		columns[field] = value
+	}
+	return columns, nil
+}
+
+// fieldOfJSONKey finds the field for a key of a JSON row. Like in CQL, keys
+// are case insensitive unless they are quoted
+func (t *MockTable) fieldOfJSONKey(name string, caseSensitive bool) (string, bool) {
+	quoted := len(name) > 1 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`)
+	if quoted {
+		name = strings.Replace(name[1:len(name)-1], `""`, `"`, -1)
+	}
+	for _, field := range t.fields {
+		if (quoted || caseSensitive) && field == name {
+			return field, true
+		}
+		if !quoted && !caseSensitive && strings.EqualFold(field, name) {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+// fromCassandraJSON decodes a JSON value into a value of the given type
+func fromCassandraJSON(raw json.RawMessage, typ reflect.Type) (interface{}, error) {
+	if typ == nil || string(raw) == "null" {
+		return nil, nil
+	}
+
+	switch typ {
+	case reflect.TypeOf(time.Time{}):
+		return parseCassandraTime(raw)
+	case byteSliceType:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	}
+
+	value := reflect.New(typ)
+	if err := json.Unmarshal(raw, value.Interface()); err != nil {
+		return nil, err
+	}
+	return value.Elem().Interface(), nil
+}
+
+func parseCassandraTime(raw json.RawMessage) (time.Time, error) {
+	var millis int64
+	if err := json.Unmarshal(raw, &millis); err == nil {
+		return time.Unix(0, millis*int64(time.Millisecond)).UTC(), nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return time.Time{}, err
+	}
+	for _, layout := range []string{cassandraTimestampFormat, "2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05", time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse timestamp %q", s)
+}
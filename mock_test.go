@@ -2,12 +2,20 @@ package gocassa
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"math/big"
+	"net"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/gocql/gocql"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"gopkg.in/inf.v0"
 )
 
 type user struct {
@@ -32,6 +40,18 @@ type point struct {
 	Y    float64
 }
 
+type flake struct {
+	Id   string
+	Name string
+}
+
+type kindedFlake struct {
+	Id    string
+	Name  string
+	Kind  string
+	Count int
+}
+
 type PostalCode string
 
 type address struct {
@@ -172,6 +192,75 @@ func (s *MockSuite) TestTableRead() {
 	s.NoError(op1.Add(op2).RunLoggedBatchWithContext(context.Background()))
 }
 
+func (s *MockSuite) TestTableTupleRelations() {
+	u1, _, u3, u4 := s.insertUsers()
+	ck := []string{"Ck1", "Ck2"}
+
+	var users []user
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1), TupleGT(ck, 1, 1)).Read(&users).Run())
+	s.Equal([]user{u4, u3}, users)
+
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1), TupleGTE(ck, 1, 2), TupleLT(ck, 2, 1)).Read(&users).Run())
+	s.Equal([]user{u4}, users)
+
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1), TupleLTE(ck, 1, 2)).Read(&users).Run())
+	s.Equal([]user{u1, u4}, users)
+
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1), NotEq("Name", "Jane")).Read(&users).Run())
+	s.Equal([]user{u1, u3}, users)
+}
+
+func (s *MockSuite) TestCollectionRelations() {
+	add := address{
+		Id:              "1",
+		County:          "London",
+		LocationPrice:   map[string]int{"A": 1, "B": 2},
+		LocationHistory: map[time.Time]string{},
+	}
+	s.NoError(s.embMapTbl.Set(add).Run())
+
+	var result []address
+	tbl := s.embMapTbl.Table()
+	s.NoError(tbl.Where(Eq("Id", "1"), ContainsKey("LocationPrice", "B")).Read(&result).Run())
+	s.Len(result, 1)
+	s.NoError(tbl.Where(Eq("Id", "1"), ContainsKey("LocationPrice", "C")).Read(&result).Run())
+	s.Len(result, 0)
+	s.NoError(tbl.Where(Eq("Id", "1"), Contains("LocationPrice", 2)).Read(&result).Run())
+	s.Len(result, 1)
+	s.NoError(tbl.Where(Eq("Id", "1"), Contains("LocationPrice", 3)).Read(&result).Run())
+	s.Len(result, 0)
+}
+
+func (s *MockSuite) TestTableAggregates() {
+	s.insertUsers()
+
+	var count int64
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1)).Count(&count).Run())
+	s.Equal(int64(3), count)
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), In("Pk2", 1, 2), GT("Ck2", 1)).Count(&count).Run())
+	s.Equal(int64(1), count)
+	s.NoError(s.tbl.Where().Count(&count).Run())
+	s.Equal(int64(5), count)
+
+	var n int
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1)).Aggregate(AggregateMax, "Ck1", &n).Run())
+	s.Equal(2, n)
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1)).Aggregate(AggregateSum, "Ck2", &n).Run())
+	s.Equal(4, n)
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1)).Aggregate(AggregateAvg, "Ck1", &n).Run())
+	s.Equal(1, n)
+	var name string
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1)).Aggregate(AggregateMin, "Name", &name).Run())
+	s.Equal("Jane", name)
+
+	s.NoError(s.tbl.Where(Eq("Pk1", 3), Eq("Pk2", 1)).Aggregate(AggregateMax, "Ck1", &n).Run())
+	s.Equal(0, n)
+
+	var keys []user
+	s.NoError(s.tbl.DistinctPartitionKeys(&keys).Run())
+	s.ElementsMatch([]user{{Pk1: 1, Pk2: 1}, {Pk1: 1, Pk2: 2}, {Pk1: 2, Pk2: 1}}, keys)
+}
+
 func (s *MockSuite) TestTableUpdate() {
 	s.insertUsers()
 
@@ -221,6 +310,113 @@ func (s *MockSuite) TestTableDeleteWithIn() {
 }
 
 // MapTable tests
+func (s *MockSuite) TestTableDeleteRange() {
+	u1, u2, _, _ := s.insertUsers()
+
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1), GTE("Ck1", 1), LT("Ck1", 2), GT("Ck2", 1)).Delete().Run())
+	var users []user
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1), LT("Ck1", 2)).Read(&users).Run())
+	s.Equal([]user{u1}, users)
+
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), Eq("Pk2", 1), GTE("Ck1", 2)).Delete().Run())
+	s.NoError(s.tbl.Where(Eq("Pk1", 1), In("Pk2", 1, 2)).Read(&users).Run())
+	s.Equal([]user{u1, u2}, users)
+}
+
+type userPreferences struct {
+	Id     string
+	Name   string
+	Tags   []string
+	Scores map[string]int
+}
+
+func (s *MockSuite) TestTableDeleteColumns() {
+	tbl := s.ks.MapTable("preferences", "Id", userPreferences{})
+	prefs := userPreferences{
+		Id:     "1",
+		Name:   "John",
+		Tags:   []string{"a", "b", "c"},
+		Scores: map[string]int{"x": 1, "y": 2},
+	}
+	s.NoError(tbl.Set(prefs).Run())
+
+	filter := tbl.Table().Where(Eq("Id", "1"))
+	s.NoError(filter.DeleteColumns(Column("Name"), ListIndex("Tags", 1), MapKey("Scores", "x")).Run())
+
+	var result userPreferences
+	s.NoError(tbl.Read("1", &result).Run())
+	s.Equal(userPreferences{Id: "1", Tags: []string{"a", "c"}, Scores: map[string]int{"y": 2}}, result)
+	// The written values aren't modified
+	s.Equal(map[string]int{"x": 1, "y": 2}, prefs.Scores)
+
+	s.Error(filter.DeleteColumns(ListIndex("Tags", 5)).Run())
+	s.Error(filter.DeleteColumns(Column("Id")).Run())
+}
+
+func (s *MockSuite) TestCollectionModifiers() {
+	tbl := s.ks.MapTable("preferences", "Id", userPreferences{})
+	prefs := userPreferences{
+		Id:     "1",
+		Tags:   []string{"b"},
+		Scores: map[string]int{"x": 1, "y": 2, "z": 3},
+	}
+	s.NoError(tbl.Set(prefs).Run())
+
+	s.NoError(tbl.Update("1", map[string]interface{}{
+		"Tags":   ListAppendAll("c", "d"),
+		"Scores": MapRemoveKeys("x", "z"),
+	}).Run())
+	s.NoError(tbl.Update("1", map[string]interface{}{"Tags": ListPrependAll("z", "a")}).Run())
+	s.NoError(tbl.Update("1", map[string]interface{}{"Tags": ListAppend("a")}).Run())
+
+	var result userPreferences
+	s.NoError(tbl.Read("1", &result).Run())
+	s.Equal([]string{"z", "a", "b", "c", "d", "a"}, result.Tags)
+	s.Equal(map[string]int{"y": 2}, result.Scores)
+
+	s.NoError(tbl.Update("1", map[string]interface{}{"Tags": ListRemove("a")}).Run())
+	s.NoError(tbl.Update("1", map[string]interface{}{"Tags": ListSetAtIndex(0, "y")}).Run())
+	s.NoError(tbl.Update("1", map[string]interface{}{"Tags": ListPrepend("x")}).Run())
+	s.NoError(tbl.Read("1", &result).Run())
+	s.Equal([]string{"x", "y", "b", "c", "d"}, result.Tags)
+
+	s.Error(tbl.Update("1", map[string]interface{}{"Tags": ListSetAtIndex(5, "y")}).Run())
+	s.Error(tbl.Update("1", map[string]interface{}{"Tags": ListAppendAll(1)}).Run())
+}
+
+func (s *MockSuite) TestTableJSON() {
+	tbl := s.ks.MapTable("preferences", "Id", userPreferences{})
+	s.NoError(tbl.Table().SetJSON([]byte(`{"id": "1", "name": "John", "tags": ["a", "b"], "scores": {"x": 1}}`)).Run())
+
+	var rows []json.RawMessage
+	s.NoError(tbl.Table().Where(Eq("Id", "1")).ReadJSON(&rows).Run())
+	s.Equal([]json.RawMessage{json.RawMessage(`{"id": "1", "name": "John", "scores": {"x": 1}, "tags": ["a", "b"]}`)}, rows)
+
+	// Columns missing from the JSON are set to null, unless DEFAULT UNSET is used
+	s.NoError(tbl.Table().SetJSON([]byte(`{"ID": "1", "tags": ["c"]}`)).WithOptions(Options{DefaultUnset: true}).Run())
+	var result userPreferences
+	s.NoError(tbl.Read("1", &result).Run())
+	s.Equal(userPreferences{Id: "1", Name: "John", Tags: []string{"c"}, Scores: map[string]int{"x": 1}}, result)
+
+	s.NoError(tbl.Table().SetJSON([]byte(`{"id": "1", "name": "Jane"}`)).Run())
+	s.NoError(tbl.Read("1", &result).Run())
+	s.Equal("Jane", result.Name)
+	s.Empty(result.Tags)
+	s.Empty(result.Scores)
+
+	s.Error(tbl.Table().SetJSON([]byte(`{"id": "1", "unknown": 1}`)).Run())
+	s.Error(tbl.Table().SetJSON([]byte(`{"id": "1", "tags": "a"}`)).Run())
+
+	sales := s.ks.MapTable("sale", "Id", typedSale{})
+	s.NoError(sales.Table().SetJSON([]byte(`{"id": "1", "created": "2021-02-03 04:05:06.789Z", "price": 10}`)).Run())
+	s.NoError(sales.Table().Where(Eq("Id", "1")).ReadJSON(&rows).WithOptions(Options{Select: []string{"Created", "Price"}}).Run())
+	s.Equal([]json.RawMessage{json.RawMessage(`{"created": "2021-02-03 04:05:06.789Z", "price": 10}`)}, rows)
+
+	var sale typedSale
+	s.NoError(sales.Read("1", &sale).Run())
+	s.Equal(time.Date(2021, 2, 3, 4, 5, 6, 789000000, time.UTC), sale.Created)
+}
+
 func (s *MockSuite) TestMapTableRead() {
 	s.insertUsers()
 	var u user
@@ -238,6 +434,51 @@ func (s *MockSuite) TestMapTableMultiRead() {
 	s.Equal("Jill", users[1].Name)
 }
 
+func (s *MockSuite) TestMapTableMultiReadParallel() {
+	for i := 10; i < 20; i++ {
+		s.NoError(s.mapTbl.Set(user{Pk1: i, Name: strconv.Itoa(i)}).Run())
+	}
+	names := func(users []user) []string {
+		names := []string{}
+		for _, u := range users {
+			names = append(names, u.Name)
+		}
+		return names
+	}
+
+	ids := []interface{}{17, 42, 11, 19, 13, 10, 43, 15}
+	for _, chunkSize := range []int{0, 1, 3, 100} {
+		var users []user
+		err := s.mapTbl.MultiRead(ids, &users).WithOptions(Options{
+			MultiReadQuery:       ParallelMultiReadQuery,
+			MultiReadChunkSize:   chunkSize,
+			MultiReadParallelism: 2,
+		}).Run()
+		s.NoError(err, "chunk size %d", chunkSize)
+		s.Equal([]string{"17", "11", "19", "13", "10", "15"}, names(users), "chunk size %d", chunkSize)
+	}
+
+	// The missing keys are only reported with MultiReadMissingKeys
+	var missing []user
+	err := s.mapTbl.MultiRead(ids, &missing).WithOptions(Options{
+		MultiReadQuery:       ParallelMultiReadQuery,
+		MultiReadMissingKeys: true,
+	}).Run()
+	s.Equal(KeysNotFoundError{Keys: []interface{}{42, 43}}, err)
+	s.Equal([]string{"17", "11", "19", "13", "10", "15"}, names(missing))
+
+	// The IN query is still the default
+	var users []user
+	s.NoError(s.mapTbl.MultiRead(ids, &users).Run())
+	s.Len(users, 6)
+
+	tbl := s.mapTbl.WithOptions(Options{MultiReadQuery: ParallelMultiReadQuery})
+	s.NoError(tbl.MultiRead([]interface{}{12, 12, 14}, &users).Run())
+	s.Equal([]string{"12", "14"}, names(users))
+	s.NoError(tbl.MultiRead([]interface{}{}, &users).Run())
+	s.Empty(users)
+}
+
 func (s *MockSuite) TestMapTableUpdate() {
 	s.insertUsers()
 	s.NoError(s.mapTbl.Update(1, map[string]interface{}{
@@ -335,6 +576,20 @@ func (s *MockSuite) TestMultiMapTableMultiRead() {
 	s.Equal("Joe", users[1].Name)
 }
 
+func (s *MockSuite) TestMultiMapTableMultiReadParallel() {
+	s.insertUsers()
+	var users []user
+	tbl := s.mmapTbl.WithOptions(Options{MultiReadQuery: ParallelMultiReadQuery})
+	s.NoError(tbl.MultiRead(1, []interface{}{2, 3, 1}, &users).Run())
+	s.Len(users, 2)
+	s.Equal("Joe", users[0].Name)
+	s.Equal("Jane", users[1].Name)
+
+	err := tbl.MultiRead(1, []interface{}{2, 3, 1}, &users).WithOptions(Options{MultiReadMissingKeys: true}).Run()
+	s.Equal(KeysNotFoundError{Keys: []interface{}{3}}, err)
+	s.Len(users, 2)
+}
+
 func (s *MockSuite) TestMultiMapTableList() {
 	s.insertUsers()
 	var users []user
@@ -356,6 +611,88 @@ func (s *MockSuite) TestMultiMapTableList() {
 	s.Equal("Joe", users[0].Name)
 }
 
+func (s *MockSuite) TestMultiMapTableListRange() {
+	for i := 1; i <= 5; i++ {
+		s.NoError(s.mmapTbl.Set(user{Pk1: 7, Pk2: i, Name: strconv.Itoa(i)}).Run())
+	}
+	names := func(users []user) []string {
+		names := []string{}
+		for _, u := range users {
+			names = append(names, u.Name)
+		}
+		return names
+	}
+
+	// Paging through the partition doesn't repeat the last row of a page
+	var users []user
+	s.NoError(s.mmapTbl.ListRange(7, IDRange{}, 2, &users).Run())
+	s.Equal([]string{"1", "2"}, names(users))
+	s.NoError(s.mmapTbl.ListRange(7, IDRange{Start: users[1].Pk2, ExclusiveStart: true}, 2, &users).Run())
+	s.Equal([]string{"3", "4"}, names(users))
+
+	s.NoError(s.mmapTbl.ListRange(7, IDRange{Start: 2, End: 4}, 0, &users).Run())
+	s.Equal([]string{"2", "3", "4"}, names(users))
+
+	s.NoError(s.mmapTbl.ListRange(7, IDRange{Descending: true}, 2, &users).Run())
+	s.Equal([]string{"5", "4"}, names(users))
+	s.NoError(s.mmapTbl.ListRange(7, IDRange{Start: 4, ExclusiveStart: true, End: 2, Descending: true}, 0, &users).Run())
+	s.Equal([]string{"3", "2"}, names(users))
+
+	// Ranges keep their order on a table read in descending order
+	desc := s.mmapTbl.WithOptions(Options{ClusteringOrder: []ClusteringOrderColumn{{Direction: DESC, Column: "Pk2"}}})
+	s.NoError(desc.ListRange(7, IDRange{Start: 2, End: 4}, 0, &users).Run())
+	s.Equal([]string{"2", "3", "4"}, names(users))
+	s.NoError(desc.ListRange(7, IDRange{Start: 4, End: 2, Descending: true}, 0, &users).Run())
+	s.Equal([]string{"4", "3", "2"}, names(users))
+}
+
+func (s *MockSuite) TestMultiMapMkTableListRange() {
+	tbl := s.ks.MultimapMultiKeyTable("stores", StorePK, StoreIndex, Store{})
+	for _, store := range []Store{
+		{City: "London", Manager: "Jane", Id: "1"},
+		{City: "London", Manager: "Jane", Id: "2"},
+		{City: "London", Manager: "Joe", Id: "1"},
+		{City: "London", Manager: "Joe", Id: "3"},
+		{City: "London", Manager: "Kim", Id: "2"},
+		{City: "Paris", Manager: "Joe", Id: "2"},
+	} {
+		s.NoError(tbl.Set(store).Run())
+	}
+	london := map[string]interface{}{CityKey: "London"}
+	keys := func(stores []Store) []string {
+		keys := []string{}
+		for _, store := range stores {
+			keys = append(keys, store.Manager+store.Id)
+		}
+		return keys
+	}
+
+	// Ids after (Jane, 2) include the ones of later managers with smaller ids
+	var stores []Store
+	s.NoError(tbl.ListRange(london, MkIDRange{
+		Start:          map[string]interface{}{ManagerKey: "Jane", IdKey: "2"},
+		ExclusiveStart: true,
+	}, 2, &stores).Run())
+	s.Equal([]string{"Joe1", "Joe3"}, keys(stores))
+
+	s.NoError(tbl.ListRange(london, MkIDRange{
+		Start: map[string]interface{}{ManagerKey: "Jane", IdKey: "2"},
+		End:   map[string]interface{}{ManagerKey: "Joe"},
+	}, 0, &stores).Run())
+	s.Equal([]string{"Jane2", "Joe1", "Joe3"}, keys(stores))
+
+	s.NoError(tbl.ListRange(london, MkIDRange{
+		Start:          map[string]interface{}{ManagerKey: "Joe", IdKey: "3"},
+		ExclusiveStart: true,
+		Descending:     true,
+	}, 2, &stores).Run())
+	s.Equal([]string{"Joe1", "Jane2"}, keys(stores))
+
+	s.Error(tbl.ListRange(london, MkIDRange{
+		Start: map[string]interface{}{IdKey: "2"},
+	}, 0, &stores).Run())
+}
+
 func (s *MockSuite) TestMultiMapTableUpdate() {
 	s.insertUsers()
 
@@ -407,6 +744,361 @@ func (s *MockSuite) TestTimeSeriesTableList() {
 	s.Equal(points[2], ps[1])
 }
 
+func (s *MockSuite) TestTimeSeriesTableListLatest() {
+	points := s.insertPoints()
+	later := point{Time: s.parseTime("2015-04-01 15:43:20"), Id: 4, User: "John"}
+	s.NoError(s.tsTbl.Set(later).Run())
+	s.NoError(s.mtsTbl.Set(later).Run())
+
+	var ps []point
+	s.NoError(s.tsTbl.ListLatest(points[0].Time, s.parseTime("2015-04-01 15:45:00"), 2, &ps).Run())
+	s.Equal([]point{later, points[2]}, ps)
+
+	// The end is exclusive, and a limit of 0 reads up to the start
+	s.NoError(s.tsTbl.ListLatest(points[1].Time, later.Time, 0, &ps).Run())
+	s.Equal([]point{points[2], points[1]}, ps)
+
+	s.NoError(s.mtsTbl.ListLatest("John", points[0].Time, s.parseTime("2015-04-01 15:45:00"), 3, &ps).Run())
+	s.Equal([]point{later, points[2], points[0]}, ps)
+
+	s.NoError(s.mtsTbl.ListLatest("Jane", points[0].Time, points[0].Time, 3, &ps).Run())
+	s.Empty(ps)
+
+	s.Error(s.tsTbl.ListLatest(points[0].Time, later.Time, 1, ps).Run())
+}
+
+func (s *MockSuite) TestTimeSeriesTableBucketQueries() {
+	start := s.parseTime("2015-04-01 15:00:00")
+	var points []point
+	for i := 0; i < 6; i++ {
+		p := point{Time: start.Add(time.Duration(i*10) * time.Minute), Id: i, User: "John"}
+		s.NoError(s.tsTbl.Set(p).Run())
+		points = append(points, p)
+	}
+	end := start.Add(time.Hour)
+
+	for _, strategy := range []BucketQuery{AutoBucketQuery, InBucketQuery, SequentialBucketQuery, ParallelBucketQuery} {
+		var ps []point
+		tbl := s.tsTbl.WithOptions(Options{BucketQuery: strategy, BucketParallelism: 2})
+		s.NoError(tbl.List(start, end, &ps).Run())
+		s.Equal(points, ps)
+
+		s.NoError(tbl.List(start, end, &ps).WithOptions(Options{Limit: 3}).Run())
+		s.Equal(points[:3], ps)
+	}
+
+	// The op options take precedence over the table options
+	var ps []point
+	op := s.tsTbl.WithOptions(Options{BucketQuery: InBucketQuery}).List(start, end, &ps)
+	s.NoError(op.WithOptions(Options{BucketQuery: ParallelBucketQuery, Limit: 4}).Run())
+	s.Equal(points[:4], ps)
+
+	// Tables in descending clustering order are read newest bucket first
+	desc := s.tsTbl.WithOptions(Options{
+		ClusteringOrder: []ClusteringOrderColumn{{Column: "Time", Direction: DESC}},
+		BucketQuery:     SequentialBucketQuery,
+	})
+	s.NoError(desc.List(start, end, &ps).WithOptions(Options{Limit: 2}).Run())
+	s.Equal([]point{points[5], points[4]}, ps)
+}
+
+func (s *MockSuite) TestTimeSeriesTableBucketing() {
+	tbl := s.ks.TimeSeriesTable("monthly_points", "Time", "Id", 0, point{}).
+		WithOptions(Options{Bucketing: MonthBuckets(nil), BucketQuery: SequentialBucketQuery})
+	var points []point
+	for i, day := range []int{1, 20, 45, 80} {
+		p := point{Time: time.Date(2015, 1, day, 12, 0, 0, 0, time.UTC), Id: i}
+		s.NoError(tbl.Set(p).Run())
+		points = append(points, p)
+	}
+
+	// Rows are stored in the partition of their month
+	var ps []point
+	s.NoError(tbl.Table().Where(Eq("bucket", time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC))).Read(&ps).Run())
+	s.Equal(points[:2], ps)
+
+	start := time.Date(2015, 1, 10, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2015, 4, 1, 0, 0, 0, 0, time.UTC)
+	s.NoError(tbl.List(start, end, &ps).Run())
+	s.Equal(points[1:], ps)
+	s.NoError(tbl.ListLatest(start, end, 2, &ps).Run())
+	s.Equal([]point{points[3], points[2]}, ps)
+
+	var p point
+	s.NoError(tbl.Read(points[2].Time, 2, &p).Run())
+	s.Equal(points[2], p)
+}
+
+func (s *MockSuite) TestShardedTimeSeriesTable() {
+	tbl := s.ks.ShardedTimeSeriesTable("points", "Time", "Id", 4, time.Minute, point{})
+	start := s.parseTime("2015-04-01 15:00:00")
+	var points []point
+	for i := 0; i < 8; i++ {
+		p := point{Time: start.Add(time.Duration(i*20) * time.Second), Id: i, User: "John"}
+		s.NoError(tbl.Set(p).Run())
+		points = append(points, p)
+	}
+
+	// The rows of a bucket are spread over its shards
+	var ps []point
+	s.NoError(tbl.Table().Where(Eq("bucket", start), Eq("shard", 0)).Read(&ps).Run())
+	s.True(len(ps) < 3)
+
+	s.NoError(tbl.List(start, start.Add(time.Hour), &ps).Run())
+	s.Equal(points, ps)
+	s.NoError(tbl.List(start, start.Add(time.Hour), &ps).WithOptions(Options{Limit: 5}).Run())
+	s.Equal(points[:5], ps)
+	s.NoError(tbl.ListLatest(start, start.Add(time.Hour), 4, &ps).Run())
+	s.Equal([]point{points[7], points[6], points[5], points[4]}, ps)
+
+	var p point
+	s.NoError(tbl.Update(points[3].Time, 3, map[string]interface{}{"User": "Jane"}).Run())
+	s.NoError(tbl.Read(points[3].Time, 3, &p).Run())
+	s.Equal("Jane", p.User)
+	s.NoError(tbl.Delete(points[3].Time, 3).Run())
+	s.Error(tbl.Read(points[3].Time, 3, &p).Run())
+}
+
+func (s *MockSuite) TestTimeUUIDSeriesTable() {
+	tbl := s.ks.TimeUUIDSeriesTable("events", "id", time.Minute, Event{})
+	events := s.insertEvents(tbl.Set)
+
+	var es []Event
+	s.NoError(tbl.List(events[0].Id.Time().Add(-time.Hour), events[8].Id.Time().Add(time.Hour), &es).Run())
+	s.Equal(events, es)
+	s.NoError(tbl.List(events[2].Id.Time(), events[5].Id.Time(), &es).Run())
+	s.Equal(events[2:5], es)
+	s.NoError(tbl.ListSince(events[3].Id, time.Hour, &es).Run())
+	s.Equal(events[4:], es)
+	s.NoError(tbl.ListSince(events[3].Id, 25*time.Second, &es).Run())
+	s.Equal(events[4:6], es)
+
+	var e Event
+	s.NoError(tbl.Update(events[1].Id, map[string]interface{}{"kind": "c"}).Run())
+	s.NoError(tbl.Read(events[1].Id, &e).Run())
+	s.Equal("c", e.Kind)
+	s.NoError(tbl.Delete(events[1].Id).Run())
+	s.Equal(RowNotFoundError{}, tbl.Read(events[1].Id, &e).Run())
+
+	random, err := gocql.RandomUUID()
+	s.NoError(err)
+	s.Error(tbl.Set(Event{Id: random}).Run())
+}
+
+func (s *MockSuite) TestMultiKeyTimeUUIDSeriesTable() {
+	tbl := s.ks.MultiKeyTimeUUIDSeriesTable("events", []string{"kind"}, "id", time.Minute, Event{})
+	events := s.insertEvents(tbl.Set)
+	a := map[string]interface{}{"kind": "a"}
+
+	var es []Event
+	s.NoError(tbl.List(a, events[0].Id.Time(), events[8].Id.Time().Add(time.Second), &es).Run())
+	s.Equal([]Event{events[0], events[2], events[4], events[6], events[8]}, es)
+	s.NoError(tbl.ListSince(a, events[2].Id, time.Hour, &es).Run())
+	s.Equal([]Event{events[4], events[6], events[8]}, es)
+	s.NoError(tbl.ListSince(map[string]interface{}{"kind": "b"}, events[2].Id, 35*time.Second, &es).Run())
+	s.Equal([]Event{events[3], events[5]}, es)
+
+	var e Event
+	s.NoError(tbl.Update(a, events[4].Id, map[string]interface{}{"kind": "a"}).Run())
+	s.NoError(tbl.Read(a, events[4].Id, &e).Run())
+	s.Equal(events[4], e)
+	s.NoError(tbl.Delete(a, events[4].Id).Run())
+	s.Equal(RowNotFoundError{}, tbl.Read(a, events[4].Id, &e).Run())
+}
+
+func (s *MockSuite) TestFlakeSeriesTableIDCodecs() {
+	start := s.parseTime("2015-04-01 15:00:00")
+	for name, tc := range map[string]struct {
+		codec     IDCodec
+		generator IDGenerator
+	}{
+		"ULID":      {ULIDs(), NewULIDGenerator()},
+		"Snowflake": {SnowflakeIDs(TwitterEpoch), NewSnowflakeGenerator(TwitterEpoch, 1)},
+	} {
+		s.Run(name, func() {
+			tbl := s.ks.FlakeSeriesTable("flakes_"+name, "Id", time.Minute, flake{}).
+				WithOptions(Options{IDCodec: tc.codec})
+			mtbl := s.ks.MultiFlakeSeriesTable("flakes_"+name, "Name", "Id", time.Minute, flake{}).
+				WithOptions(Options{IDCodec: tc.codec})
+
+			var flakes []flake
+			for i := 0; i < 4; i++ {
+				id, err := tc.generator.NewID(start.Add(time.Duration(i) * 40 * time.Second))
+				s.NoError(err)
+				f := flake{Id: id, Name: "John"}
+				s.NoError(tbl.Set(f).Run())
+				s.NoError(mtbl.Set(f).Run())
+				flakes = append(flakes, f)
+			}
+
+			var fs []flake
+			s.NoError(tbl.List(start, start.Add(time.Hour), &fs).Run())
+			s.Equal(flakes, fs)
+			if tc.codec.Lexical() {
+				s.NoError(tbl.ListSince(flakes[1].Id, time.Minute, &fs).Run())
+				s.Equal(flakes[2:3], fs)
+			} else {
+				s.Error(tbl.ListSince(flakes[1].Id, time.Minute, &fs).Run())
+			}
+			s.NoError(mtbl.List("John", start, start.Add(90*time.Second), &fs).Run())
+			s.Equal(flakes[:3], fs)
+
+			var f flake
+			s.NoError(tbl.Read(flakes[2].Id, &f).Run())
+			s.Equal(flakes[2], f)
+			s.NoError(mtbl.Delete("John", flakes[2].Id).Run())
+			s.Equal(RowNotFoundError{}, mtbl.Read("John", flakes[2].Id, &f).Run())
+			s.Error(tbl.Read("id_1DQMRBWwBjDq5eHs4BRfCh", &f).Run())
+		})
+	}
+}
+
+func (s *MockSuite) TestMultiKeyFlakeSeriesTable() {
+	tbl := s.ks.MultiKeyFlakeSeriesTable("flakes", []string{"Name", "Kind"}, "Id", time.Minute, kindedFlake{})
+	start := s.parseTime("2015-04-01 15:00:00")
+	ids := NewBigflakeGenerator("flake", 1)
+	var flakes []kindedFlake
+	for i := 0; i < 6; i++ {
+		id, err := ids.NewID(start.Add(time.Duration(i) * 30 * time.Second))
+		s.NoError(err)
+		f := kindedFlake{Id: id, Name: "John", Kind: "a"}
+		if i%2 == 1 {
+			f.Kind = "b"
+		}
+		s.NoError(tbl.Set(f).Run())
+		flakes = append(flakes, f)
+	}
+	a := map[string]interface{}{"Name": "John", "Kind": "a"}
+
+	var fs []kindedFlake
+	s.NoError(tbl.List(a, start, start.Add(time.Hour), &fs).Run())
+	s.Equal([]kindedFlake{flakes[0], flakes[2], flakes[4]}, fs)
+	s.NoError(tbl.List(map[string]interface{}{"Name": "John", "Kind": "b"}, start, start.Add(90*time.Second), &fs).Run())
+	s.Equal([]kindedFlake{flakes[1]}, fs)
+	s.NoError(tbl.ListSince(a, flakes[0].Id, 90*time.Second, &fs).Run())
+	s.Equal([]kindedFlake{flakes[2]}, fs)
+
+	var f kindedFlake
+	s.NoError(tbl.Update(a, flakes[2].Id, map[string]interface{}{"Count": 3}).Run())
+	s.NoError(tbl.Read(a, flakes[2].Id, &f).Run())
+	s.Equal(3, f.Count)
+	s.NoError(tbl.Delete(a, flakes[2].Id).Run())
+	s.Equal(RowNotFoundError{}, tbl.Read(a, flakes[2].Id, &f).Run())
+	s.Error(tbl.Read(a, "invalid", &f).Run())
+}
+
+func (s *MockSuite) TestBucketRetention() {
+	points := s.insertPoints()
+	start := s.parseTime("2015-04-01 15:40:00")
+	p := point{Time: s.parseTime("2015-04-01 15:42:30"), Id: 4, User: "John"}
+	s.NoError(s.tsTbl.Set(p).Run())
+	s.NoError(s.mtsTbl.Set(p).Run())
+	ctx := context.Background()
+
+	// The bucket of 15:42 isn't over at the cutoff
+	cutoff := s.parseTime("2015-04-01 15:42:30")
+	buckets, err := BucketRetention{DryRun: true}.DeleteBefore(ctx, s.tsTbl.Buckets(start), cutoff)
+	s.NoError(err)
+	s.Len(buckets, 2)
+	s.True(buckets[0].Equal(start) && buckets[1].Equal(start.Add(time.Minute)), buckets)
+	var ps []point
+	s.NoError(s.tsTbl.List(start, cutoff, &ps).Run())
+	s.Len(ps, 4)
+
+	buckets, err = BucketRetention{Rate: 1000}.DeleteBefore(ctx, s.tsTbl.Buckets(start), cutoff)
+	s.NoError(err)
+	s.Len(buckets, 2)
+	s.True(buckets[0].Equal(start) && buckets[1].Equal(start.Add(time.Minute)), buckets)
+	s.NoError(s.tsTbl.List(start, cutoff, &ps).Run())
+	s.Equal([]point{p}, ps)
+
+	// Multi recipes delete the buckets of a single value
+	_, err = BucketRetention{}.DeleteBefore(ctx, s.mtsTbl.Buckets("John", start), cutoff)
+	s.NoError(err)
+	s.NoError(s.mtsTbl.List("John", start, cutoff, &ps).Run())
+	s.Equal([]point{p}, ps)
+	s.NoError(s.mtsTbl.List("Jane", start, cutoff, &ps).Run())
+	s.Equal([]point{points[1]}, ps)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	buckets, err = BucketRetention{}.DeleteBefore(cancelled, s.mtsTbl.Buckets("Jane", start), cutoff)
+	s.Equal(context.Canceled, err)
+	s.Empty(buckets)
+}
+
+func (s *MockSuite) TestRecipeRetention() {
+	s.insertPoints()
+	start := s.parseTime("2015-04-01 15:40:00")
+	cutoff := s.parseTime("2015-04-01 15:42:00")
+	ctx := context.Background()
+
+	buckets, err := s.tsTbl.Retention(ctx, start, cutoff, BucketRetention{DryRun: true})
+	s.NoError(err)
+	s.Len(buckets, 2)
+	s.True(buckets[0].Equal(start) && buckets[1].Equal(start.Add(time.Minute)), buckets)
+	var ps []point
+	s.NoError(s.tsTbl.List(start, cutoff, &ps).Run())
+	s.Len(ps, 3)
+
+	buckets, err = s.mtsTbl.Retention(ctx, "John", start, cutoff, BucketRetention{})
+	s.NoError(err)
+	s.Len(buckets, 2)
+	s.NoError(s.mtsTbl.List("John", start, cutoff, &ps).Run())
+	s.Empty(ps)
+
+	tbl := s.ks.FlakeSeriesTable("retention", "Id", time.Minute, flake{})
+	ids := NewBigflakeGenerator("flake", 1)
+	var flakes []flake
+	for i := 0; i < 3; i++ {
+		id, err := ids.NewID(start.Add(time.Duration(i) * time.Minute))
+		s.NoError(err)
+		flakes = append(flakes, flake{Id: id, Name: "John"})
+		s.NoError(tbl.Set(flakes[i]).Run())
+	}
+
+	buckets, err = tbl.Retention(ctx, start, cutoff, BucketRetention{DryRun: true})
+	s.NoError(err)
+	s.Len(buckets, 2)
+	s.True(buckets[0].Equal(start) && buckets[1].Equal(start.Add(time.Minute)), buckets)
+	var fs []flake
+	s.NoError(tbl.List(start, start.Add(time.Hour), &fs).Run())
+	s.Equal(flakes, fs)
+
+	_, err = tbl.Retention(ctx, start, cutoff, BucketRetention{})
+	s.NoError(err)
+	s.NoError(tbl.List(start, start.Add(time.Hour), &fs).Run())
+	s.Equal(flakes[2:], fs)
+}
+
+func (s *MockSuite) TestShardedRecipeRetention() {
+	tbl := s.ks.ShardedTimeSeriesTable("retention", "Time", "Id", 8, time.Minute, point{})
+	start := s.parseTime("2015-04-01 15:40:00")
+	ctx := context.Background()
+
+	// A few rows leave most shards of the buckets empty
+	var points []point
+	for i := 0; i < 3; i++ {
+		p := point{Time: start.Add(time.Duration(i) * 40 * time.Second), Id: i + 1, User: "John"}
+		s.NoError(tbl.Set(p).Run())
+		points = append(points, p)
+	}
+	shards := map[int]bool{}
+	for _, p := range points {
+		shard, err := tbl.(*shardedTimeSeriesT).shard(p.Id)
+		s.NoError(err)
+		shards[shard] = true
+	}
+	s.Len(shards, 3)
+
+	buckets, err := tbl.Retention(ctx, start, start.Add(2*time.Minute), BucketRetention{})
+	s.NoError(err)
+	s.Len(buckets, 2)
+	var ps []point
+	s.NoError(tbl.List(start, start.Add(time.Hour), &ps).Run())
+	s.Empty(ps)
+}
+
 func (s *MockSuite) TestWithOptions() {
 	points := s.insertPoints()
 	var ps []point
@@ -532,6 +1224,282 @@ func (s *MockSuite) TestEmbedMapRead() {
 	s.Equal(expectedAddresses[1], actualAddress)
 }
 
+func (s *MockSuite) TestUserDefinedTypes() {
+	tbl := s.ks.MapTable("customers", "Id", customerWithAddress{})
+	c := customerWithAddress{
+		Id:       "1",
+		Home:     postalAddress{Street: "1 Main St", City: "London", Location: geoPoint{51.5, -0.1}},
+		Previous: []postalAddress{{Street: "2 High St", City: "Leeds"}},
+		Locations: map[string]geoPoint{
+			"work": {51.4, -0.2},
+		},
+	}
+	s.NoError(tbl.Set(c).Run())
+
+	var actual customerWithAddress
+	s.NoError(tbl.Read("1", &actual).Run())
+	s.Equal(c, actual)
+
+	s.NoError(tbl.Update("1", map[string]interface{}{
+		"Home":      postalAddress{City: "Paris"},
+		"Locations": MapSetField("gym", geoPoint{48.8, 2.3}),
+	}).Run())
+	s.NoError(tbl.Read("1", &actual).Run())
+	s.Equal(postalAddress{City: "Paris"}, actual.Home)
+	s.Equal(map[string]geoPoint{"work": {51.4, -0.2}, "gym": {48.8, 2.3}}, actual.Locations)
+}
+
+func (s *MockSuite) TestSetsAndTuples() {
+	tbl := s.ks.MapTable("tagged", "Id", taggedCollections{})
+	c := taggedCollections{
+		Id:       "1",
+		Tags:     []string{"b", "a", "b"},
+		Position: geoPoint{51.5, -0.1},
+		Groups:   map[string][]int{"x": {1, 2}},
+	}
+	s.NoError(tbl.Set(c).Run())
+	s.Equal([]string{"b", "a", "b"}, c.Tags)
+
+	var actual taggedCollections
+	s.NoError(tbl.Read("1", &actual).Run())
+	s.Equal([]string{"a", "b"}, actual.Tags)
+	s.Equal(c.Position, actual.Position)
+	s.Equal(c.Groups, actual.Groups)
+
+	s.NoError(tbl.Update("1", map[string]interface{}{
+		"tags": SetAdd("d", "c", "a"),
+	}).Run())
+	s.NoError(tbl.Read("1", &actual).Run())
+	s.Equal([]string{"a", "b", "c", "d"}, actual.Tags)
+
+	s.NoError(tbl.Update("1", map[string]interface{}{
+		"tags": SetRemove("b", "z"),
+	}).Run())
+	s.NoError(tbl.Read("1", &actual).Run())
+	s.Equal([]string{"a", "c", "d"}, actual.Tags)
+}
+
+func (s *MockSuite) TestScalarTypes() {
+	type reading struct {
+		Sensor  net.IP
+		Seq     int16
+		Value   *inf.Dec
+		Counted *big.Int
+	}
+	tbl := s.ks.MultimapTable("readings", "Sensor", "Seq", reading{})
+	sensor := net.ParseIP("10.0.0.1")
+	for i := int16(-2); i <= 2; i++ {
+		s.NoError(tbl.Set(reading{sensor, i, inf.NewDec(int64(i), 1), big.NewInt(int64(i))}).Run())
+	}
+
+	var actual reading
+	s.NoError(tbl.Read(net.IPv4(10, 0, 0, 1), int16(1), &actual).Run())
+	s.Equal(inf.NewDec(1, 1), actual.Value)
+	s.Equal(big.NewInt(1), actual.Counted)
+
+	var results []reading
+	s.NoError(tbl.Table().Where(Eq("Sensor", sensor), GT("Seq", int16(-1)), LTE("Value", inf.NewDec(1, 1))).Read(&results).Run())
+	s.Len(results, 2)
+}
+
+type userEvent struct {
+	UserId      string
+	EventId     string
+	DisplayName string `cql:"DisplayName,static"`
+	Kind        string
+}
+
+func (s *MockSuite) TestStaticColumns() {
+	tbl := s.ks.MultimapTable("user_events", "UserId", "EventId", userEvent{})
+	s.NoError(tbl.Set(userEvent{"u1", "e1", "Alice", "login"}).Run())
+	s.NoError(tbl.Set(userEvent{"u1", "e2", "Alice A.", "logout"}).Run())
+	s.NoError(tbl.Set(userEvent{"u2", "e1", "Bob", "login"}).Run())
+
+	var events []userEvent
+	s.NoError(tbl.List("u1", nil, 0, &events).Run())
+	s.Equal([]userEvent{{"u1", "e1", "Alice A.", "login"}, {"u1", "e2", "Alice A.", "logout"}}, events)
+
+	s.NoError(tbl.UpdateStatic("u1", map[string]interface{}{"DisplayName": "Alice B."}).Run())
+	var event userEvent
+	s.NoError(tbl.Read("u1", "e1", &event).Run())
+	s.Equal("Alice B.", event.DisplayName)
+	s.NoError(tbl.Read("u2", "e1", &event).Run())
+	s.Equal("Bob", event.DisplayName)
+
+	// The static cells remain once all rows are deleted
+	s.NoError(tbl.Delete("u1", "e1").Run())
+	s.NoError(tbl.Delete("u1", "e2").Run())
+	s.NoError(tbl.List("u1", nil, 0, &events).Run())
+	s.Equal([]userEvent{{UserId: "u1", DisplayName: "Alice B."}}, events)
+	s.IsType(RowNotFoundError{}, tbl.Read("u1", "e1", &event).Run())
+
+	s.NoError(tbl.DeleteAll("u1").Run())
+	s.NoError(tbl.List("u1", nil, 0, &events).Run())
+	s.Empty(events)
+
+	type timedEvent struct {
+		UserId      string
+		EventId     string
+		Time        time.Time
+		DisplayName string `cql:"DisplayName,static"`
+	}
+	ts := s.ks.MultiTimeSeriesTable("user_events_ts", "UserId", "Time", "EventId", time.Hour, timedEvent{})
+	start := s.parseTime("2015-04-01 15:00:00")
+	s.NoError(ts.Set(timedEvent{"u1", "e1", start.Add(time.Minute), "Alice"}).Run())
+	s.NoError(ts.Set(timedEvent{"u1", "e2", start.Add(2 * time.Minute), "Alice A."}).Run())
+
+	var timed []timedEvent
+	s.NoError(ts.List("u1", start, start.Add(time.Hour), &timed).Run())
+	s.Len(timed, 2)
+	for _, e := range timed {
+		s.Equal("Alice A.", e.DisplayName)
+	}
+}
+
+type staticEvent struct {
+	UserId      string
+	Id          string
+	Time        time.Time
+	DisplayName string `cql:"DisplayName,static"`
+}
+
+type staticUUIDEvent struct {
+	UserId      string
+	Id          gocql.UUID `cql:"Id,type=timeuuid"`
+	DisplayName string     `cql:"DisplayName,static"`
+}
+
+func (s *MockSuite) TestStaticColumnsRecipes() {
+	start := s.parseTime("2015-04-01 15:00:00")
+	end := start.Add(2 * time.Hour)
+	// The first two rows share a bucket, the third one is in the next bucket
+	times := []time.Time{start.Add(time.Minute), start.Add(2 * time.Minute), start.Add(90 * time.Minute)}
+	// Every recipe goes back in time, which a shared generator wouldn't do
+	flakeID := func(at time.Time) string {
+		id, err := NewBigflakeGenerator("event", 1).NewID(at)
+		s.NoError(err)
+		return id
+	}
+	user := map[string]interface{}{"UserId": "u1"}
+	displayName := map[string]interface{}{"DisplayName": "Alice A."}
+
+	ts := s.ks.TimeSeriesTable("static_ts", "Time", "Id", time.Hour, staticEvent{})
+	sts := s.ks.ShardedTimeSeriesTable("static_sts", "Time", "Id", 4, time.Hour, staticEvent{})
+	mts := s.ks.MultiTimeSeriesTable("static_mts", "UserId", "Time", "Id", time.Hour, staticEvent{})
+	mkts := s.ks.MultiKeyTimeSeriesTable("static_mkts", []string{"UserId"}, "Time", []string{"Id"}, time.Hour, staticEvent{})
+	fs := s.ks.FlakeSeriesTable("static_fs", "Id", time.Hour, staticEvent{})
+	mfs := s.ks.MultiFlakeSeriesTable("static_mfs", "UserId", "Id", time.Hour, staticEvent{})
+	mkfs := s.ks.MultiKeyFlakeSeriesTable("static_mkfs", []string{"UserId"}, "Id", time.Hour, staticEvent{})
+
+	for _, tc := range []struct {
+		name   string
+		id     func(at time.Time) string
+		set    func(row interface{}) Op
+		update func(at time.Time) Op
+		list   func(result *[]staticEvent) Op
+	}{
+		{
+			name:   "TimeSeriesTable",
+			set:    ts.Set,
+			update: func(at time.Time) Op { return ts.UpdateStatic(at, displayName) },
+			list:   func(result *[]staticEvent) Op { return ts.List(start, end, result) },
+		},
+		{
+			name:   "ShardedTimeSeriesTable",
+			set:    sts.Set,
+			update: func(at time.Time) Op { return sts.UpdateStatic(at, displayName) },
+			list:   func(result *[]staticEvent) Op { return sts.List(start, end, result) },
+		},
+		{
+			name:   "MultiTimeSeriesTable",
+			set:    mts.Set,
+			update: func(at time.Time) Op { return mts.UpdateStatic("u1", at, displayName) },
+			list:   func(result *[]staticEvent) Op { return mts.List("u1", start, end, result) },
+		},
+		{
+			name:   "MultiKeyTimeSeriesTable",
+			set:    mkts.Set,
+			update: func(at time.Time) Op { return mkts.UpdateStatic(user, at, displayName) },
+			list:   func(result *[]staticEvent) Op { return mkts.List(user, start, end, result) },
+		},
+		{
+			name:   "FlakeSeriesTable",
+			id:     flakeID,
+			set:    fs.Set,
+			update: func(at time.Time) Op { return fs.UpdateStatic(at, displayName) },
+			list:   func(result *[]staticEvent) Op { return fs.List(start, end, result) },
+		},
+		{
+			name:   "MultiFlakeSeriesTable",
+			id:     flakeID,
+			set:    mfs.Set,
+			update: func(at time.Time) Op { return mfs.UpdateStatic("u1", at, displayName) },
+			list:   func(result *[]staticEvent) Op { return mfs.List("u1", start, end, result) },
+		},
+		{
+			name:   "MultiKeyFlakeSeriesTable",
+			id:     flakeID,
+			set:    mkfs.Set,
+			update: func(at time.Time) Op { return mkfs.UpdateStatic(user, at, displayName) },
+			list:   func(result *[]staticEvent) Op { return mkfs.List(user, start, end, result) },
+		},
+	} {
+		for i, at := range times {
+			e := staticEvent{UserId: "u1", Id: strconv.Itoa(i), Time: at, DisplayName: "Alice"}
+			if tc.id != nil {
+				e.Id = tc.id(at)
+			}
+			s.NoError(tc.set(e).Run(), tc.name)
+		}
+		s.NoError(tc.update(start).Run(), tc.name)
+
+		var events []staticEvent
+		s.NoError(tc.list(&events).Run(), tc.name)
+		names := []string{}
+		for _, e := range events {
+			names = append(names, e.DisplayName)
+		}
+		s.Equal([]string{"Alice A.", "Alice A.", "Alice"}, names, tc.name)
+	}
+
+	// Time UUID series
+	uuidTbl := s.ks.TimeUUIDSeriesTable("static_tus", "Id", time.Hour, staticUUIDEvent{})
+	mkUUIDTbl := s.ks.MultiKeyTimeUUIDSeriesTable("static_mktus", []string{"UserId"}, "Id", time.Hour, staticUUIDEvent{})
+	for _, at := range times {
+		e := staticUUIDEvent{UserId: "u1", Id: gocql.UUIDFromTime(at), DisplayName: "Alice"}
+		s.NoError(uuidTbl.Set(e).Run())
+		s.NoError(mkUUIDTbl.Set(e).Run())
+	}
+	s.NoError(uuidTbl.UpdateStatic(start, displayName).Run())
+	s.NoError(mkUUIDTbl.UpdateStatic(user, start, displayName).Run())
+	for _, op := range []func(*[]staticUUIDEvent) Op{
+		func(result *[]staticUUIDEvent) Op { return uuidTbl.List(start, end, result) },
+		func(result *[]staticUUIDEvent) Op { return mkUUIDTbl.List(user, start, end, result) },
+	} {
+		var events []staticUUIDEvent
+		s.NoError(op(&events).Run())
+		s.Len(events, 3)
+		s.Equal("Alice A.", events[0].DisplayName)
+		s.Equal("Alice A.", events[1].DisplayName)
+		s.Equal("Alice", events[2].DisplayName)
+	}
+
+	// Multimaps with composite keys
+	mkTbl := s.ks.MultimapMultiKeyTable("static_mmk", []string{"UserId"}, []string{"Id"}, staticEvent{})
+	s.NoError(mkTbl.Set(staticEvent{UserId: "u1", Id: "e1", DisplayName: "Alice"}).Run())
+	s.NoError(mkTbl.Set(staticEvent{UserId: "u1", Id: "e2", DisplayName: "Alice"}).Run())
+	s.NoError(mkTbl.Set(staticEvent{UserId: "u2", Id: "e1", DisplayName: "Bob"}).Run())
+	s.NoError(mkTbl.UpdateStatic(user, displayName).Run())
+	var events []staticEvent
+	s.NoError(mkTbl.List(user, nil, 0, &events).Run())
+	s.Len(events, 2)
+	s.Equal("Alice A.", events[0].DisplayName)
+	s.Equal("Alice A.", events[1].DisplayName)
+	var event staticEvent
+	s.NoError(mkTbl.Read(map[string]interface{}{"UserId": "u2"}, map[string]interface{}{"Id": "e1"}, &event).Run())
+	s.Equal("Bob", event.DisplayName)
+}
+
 // Helper functions
 func (s *MockSuite) insertPoints() []point {
 	points := []point{
@@ -567,6 +1535,26 @@ func (s *MockSuite) insertPoints() []point {
 	return points
 }
 
+// insertEvents sets events 10 seconds apart, of kinds a and b in turn. They
+// span the time at which the low bits of the timestamp of time UUIDs wrap
+// around, so that their bytes aren't ordered like their times.
+func (s *MockSuite) insertEvents(set func(interface{}) Op) []Event {
+	start := s.parseTime("2015-04-01 15:00:00")
+	low := binary.BigEndian.Uint32(gocql.UUIDFromTime(start).Bytes())
+	wrap := start.Add(time.Duration(math.MaxUint32-low) * 100)
+
+	var events []Event
+	for i := 0; i < 9; i++ {
+		e := Event{Id: gocql.UUIDFromTime(wrap.Add(time.Duration(i-4) * 10 * time.Second)), Kind: "a"}
+		if i%2 == 1 {
+			e.Kind = "b"
+		}
+		s.NoError(set(e).Run())
+		events = append(events, e)
+	}
+	return events
+}
+
 func (s *MockSuite) insertUsers() (user, user, user, user) {
 	u1 := user{
 		Pk1:  1,
@@ -21,6 +21,11 @@ const (
 	ModifierMapSetFields                       // set values from the provided map
 	ModifierMapSetField                        // update a value for a specific key
 	ModifierCounterIncrement                   // increment a counter
+	ModifierSetAdd                             // add elements to a set
+	ModifierSetRemove                          // remove elements from a set
+	ModifierMapRemoveKeys                      // remove keys from a map
+	ModifierListAppendAll                      // append many values to the end of a list
+	ModifierListPrependAll                     // prepend many values to the beginning of a list
 )
 
 type Modifier struct {
@@ -49,6 +54,13 @@ func (m Modifier) Operation() ModifierOp {
 //     to be set in the underlying map
 //   - ModifierCounterIncrement returns 1 element (int) with how much the value
 //     should be incremented by (or decremented if the value is negative)
+//   - ModifierSetAdd returns the elements (interface{}) to be added to the set
+//   - ModifierSetRemove returns the elements (interface{}) to be removed from
+//     the set
+//   - ModifierMapRemoveKeys returns the keys (interface{}) to be removed from
+//     the map
+//   - ModifierListAppendAll and ModifierListPrependAll return the values
+//     (interface{}) to be appended or prepended, in order
 func (m Modifier) Args() []interface{} {
 	return m.args
 }
@@ -110,6 +122,22 @@ func CounterIncrement(value int) Modifier {
 	}
 }
 
+// SetAdd adds the given values to a set, values already in the set are ignored
+func SetAdd(values ...interface{}) Modifier {
+	return Modifier{
+		op:   ModifierSetAdd,
+		args: values,
+	}
+}
+
+// SetRemove removes the given values from a set
+func SetRemove(values ...interface{}) Modifier {
+	return Modifier{
+		op:   ModifierSetRemove,
+		args: values,
+	}
+}
+
 func (m Modifier) cql(name string) (string, []interface{}) {
 	str := ""
 	vals := []interface{}{}
@@ -158,6 +186,43 @@ func (m Modifier) cql(name string) (string, []interface{}) {
 			str = fmt.Sprintf("%s = %s - ?", name, name)
 			vals = append(vals, -val)
 		}
+	case ModifierSetAdd:
+		str = fmt.Sprintf("%s = %s + ?", name, name)
+		vals = append(vals, m.args)
+	case ModifierSetRemove, ModifierMapRemoveKeys:
+		str = fmt.Sprintf("%s = %s - ?", name, name)
+		vals = append(vals, m.args)
+	case ModifierListAppendAll:
+		str = fmt.Sprintf("%s = %s + ?", name, name)
+		vals = append(vals, m.args)
+	case ModifierListPrependAll:
+		str = fmt.Sprintf("%s = ? + %s", name, name)
+		vals = append(vals, m.args)
 	}
 	return str, vals
 }
+
+// MapRemoveKeys removes the given keys, and their values, from a map
+func MapRemoveKeys(keys ...interface{}) Modifier {
+	return Modifier{
+		op:   ModifierMapRemoveKeys,
+		args: keys,
+	}
+}
+
+// ListAppendAll appends the given values, in order, to the end of the list
+func ListAppendAll(values ...interface{}) Modifier {
+	return Modifier{
+		op:   ModifierListAppendAll,
+		args: values,
+	}
+}
+
+// ListPrependAll prepends the given values to the front of the list, so that
+// the list starts with the values in the given order
+func ListPrependAll(values ...interface{}) Modifier {
+	return Modifier{
+		op:   ModifierListPrependAll,
+		args: values,
+	}
+}
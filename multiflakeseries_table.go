@@ -1,6 +1,7 @@
 package gocassa
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -21,6 +22,9 @@ func (o *multiFlakeSeriesT) CreateStatement() (Statement, error) { return o.Tabl
 func (o *multiFlakeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *multiFlakeSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
 
 func (o *multiFlakeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -32,24 +36,24 @@ func (o *multiFlakeSeriesT) Set(v interface{}) Op {
 		panic(fmt.Sprintf("Id field (%s) is not present or is not a string", o.idField))
 	}
 
-	timestamp, err := flakeToTime(id)
+	timestamp, err := o.idCodec().Time(id)
 	if err != nil {
 		return errOp{err: err}
 	}
 
 	m[flakeTimestampFieldName] = timestamp
-	m[bucketFieldName] = bucket(timestamp, o.bucketSize)
+	m[bucketFieldName] = o.bucketing().Bucket(timestamp)
 
 	return o.Table().
 		Set(m)
 }
 
 func (o *multiFlakeSeriesT) Update(v interface{}, id string, m map[string]interface{}) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := o.idCodec().Time(id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timestamp)
 
 	return o.Table().
 		Where(Eq(o.indexField, v),
@@ -59,12 +63,16 @@ func (o *multiFlakeSeriesT) Update(v interface{}, id string, m map[string]interf
 		Update(m)
 }
 
+func (o *multiFlakeSeriesT) UpdateStatic(v interface{}, timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(v, timeStamp).Filter().Update(m)
+}
+
 func (o *multiFlakeSeriesT) Delete(v interface{}, id string) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := o.idCodec().Time(id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timestamp)
 
 	return o.Table().
 		Where(Eq(o.indexField, v),
@@ -75,11 +83,11 @@ func (o *multiFlakeSeriesT) Delete(v interface{}, id string) Op {
 }
 
 func (o *multiFlakeSeriesT) Read(v interface{}, id string, pointer interface{}) Op {
-	timestamp, err := flakeToTime(id)
+	timestamp, err := o.idCodec().Time(id)
 	if err != nil {
 		return errOp{err: err}
 	}
-	bucket := bucket(timestamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timestamp)
 	return o.Table().
 		Where(Eq(o.indexField, v),
 			Eq(bucketFieldName, bucket),
@@ -89,59 +97,55 @@ func (o *multiFlakeSeriesT) Read(v interface{}, id string, pointer interface{})
 }
 
 func (o *multiFlakeSeriesT) List(v interface{}, startTime, endTime time.Time, pointerToASlice interface{}) Op {
-	buckets := []interface{}{}
-	for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
-		buckets = append(buckets, bucket.Bucket())
-	}
-	return o.Table().
-		Where(Eq(o.indexField, v),
-			In(bucketFieldName, buckets...),
-			GTE(flakeTimestampFieldName, startTime),
-			LT(flakeTimestampFieldName, endTime)).
-		Read(pointerToASlice)
+	return newBucketsOp(o.Buckets(v, startTime), o.Buckets(v, endTime), endTime,
+		[]Relation{GTE(flakeTimestampFieldName, startTime), LT(flakeTimestampFieldName, endTime)},
+		pointerToASlice)
+}
+
+func (o *multiFlakeSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
+}
+
+func (o *multiFlakeSeriesT) idCodec() IDCodec {
+	return tableIDCodec(o.Table())
 }
 
 func (o *multiFlakeSeriesT) Buckets(v interface{}, start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		bucketing: o.bucketing(),
 		field:     bucketFieldName,
 		invariant: o.Table().Where(Eq(o.indexField, v))}
 }
 
+func (o *multiFlakeSeriesT) Retention(ctx context.Context, v interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(v, start), cutoff)
+}
+
 func (o *multiFlakeSeriesT) ListSince(v interface{}, id string, window time.Duration, pointerToASlice interface{}) Op {
-	startTime, err := flakeToTime(id)
+	codec, err := sinceCodec(o.Table())
 	if err != nil {
 		return errOp{err: err}
 	}
-
-	var endTime time.Time
-	if window == 0 {
-		// no window set - so go up until 5 mins in the future
-		endTime = time.Now().Add(5 * time.Minute)
-	} else {
-		endTime = startTime.Add(window)
+	startTime, err := codec.Time(id)
+	if err != nil {
+		return errOp{err: err}
 	}
 
-	buckets := []interface{}{}
-	for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
-		buckets = append(buckets, bucket.Bucket())
-	}
+	endTime := sinceEndTime(startTime, window)
 
-	return o.Table().
-		Where(Eq(o.indexField, v),
-			In(bucketFieldName, buckets),
-			GTE(flakeTimestampFieldName, startTime),
-			LT(flakeTimestampFieldName, endTime),
-			GT(o.idField, id)).
-		Read(pointerToASlice)
+	return newBucketsOp(o.Buckets(v, startTime), o.Buckets(v, endTime), endTime,
+		[]Relation{GTE(flakeTimestampFieldName, startTime), LT(flakeTimestampFieldName, endTime), GT(o.idField, id)},
+		pointerToASlice)
 }
 
 func (o *multiFlakeSeriesT) WithOptions(opt Options) MultiFlakeSeriesTable {
-	return &multiFlakeSeriesT{
+	result := &multiFlakeSeriesT{
 		t:          o.Table().WithOptions(opt),
 		indexField: o.indexField,
 		idField:    o.idField,
 		bucketSize: o.bucketSize,
 	}
+	reregister(o, result)
+	return result
 }
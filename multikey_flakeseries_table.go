@@ -0,0 +1,162 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type multiKeyFlakeSeriesT struct {
+	t           Table
+	indexFields []string
+	idField     string
+	bucketSize  time.Duration
+}
+
+func (o *multiKeyFlakeSeriesT) Table() Table            { return o.t }
+func (o *multiKeyFlakeSeriesT) Create() error           { return o.Table().Create() }
+func (o *multiKeyFlakeSeriesT) CreateIfNotExist() error { return o.Table().CreateIfNotExist() }
+func (o *multiKeyFlakeSeriesT) Name() string            { return o.Table().Name() }
+func (o *multiKeyFlakeSeriesT) Recreate() error         { return o.Table().Recreate() }
+func (o *multiKeyFlakeSeriesT) CreateStatement() (Statement, error) {
+	return o.Table().CreateStatement()
+}
+func (o *multiKeyFlakeSeriesT) CreateIfNotExistStatement() (Statement, error) {
+	return o.Table().CreateIfNotExistStatement()
+}
+func (o *multiKeyFlakeSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
+
+func (o *multiKeyFlakeSeriesT) Set(v interface{}) Op {
+	m, ok := toMap(v)
+	if !ok {
+		panic("Can't set: not able to convert")
+	}
+	id, ok := m[o.idField].(string)
+	if !ok {
+		panic(fmt.Sprintf("Id field (%s) is not present or is not a string", o.idField))
+	}
+
+	timestamp, err := o.idCodec().Time(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	m[flakeTimestampFieldName] = timestamp
+	m[bucketFieldName] = o.bucketing().Bucket(timestamp)
+
+	return o.Table().Set(m)
+}
+
+// rowRelations returns the relations selecting the row of the given id
+func (o *multiKeyFlakeSeriesT) rowRelations(v map[string]interface{}, id string) ([]Relation, error) {
+	timestamp, err := o.idCodec().Time(id)
+	if err != nil {
+		return nil, err
+	}
+	relations := o.ListOfEqualRelations(v)
+	relations = append(relations, Eq(bucketFieldName, o.bucketing().Bucket(timestamp)))
+	relations = append(relations, Eq(flakeTimestampFieldName, timestamp))
+	relations = append(relations, Eq(o.idField, id))
+	return relations, nil
+}
+
+func (o *multiKeyFlakeSeriesT) Update(v map[string]interface{}, id string, m map[string]interface{}) Op {
+	relations, err := o.rowRelations(v, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return o.Table().
+		Where(relations...).
+		Update(m)
+}
+
+func (o *multiKeyFlakeSeriesT) UpdateStatic(v map[string]interface{}, timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(v, timeStamp).Filter().Update(m)
+}
+
+func (o *multiKeyFlakeSeriesT) Delete(v map[string]interface{}, id string) Op {
+	relations, err := o.rowRelations(v, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return o.Table().
+		Where(relations...).
+		Delete()
+}
+
+func (o *multiKeyFlakeSeriesT) Read(v map[string]interface{}, id string, pointer interface{}) Op {
+	relations, err := o.rowRelations(v, id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	return o.Table().
+		Where(relations...).
+		ReadOne(pointer)
+}
+
+func (o *multiKeyFlakeSeriesT) List(v map[string]interface{}, startTime, endTime time.Time, pointerToASlice interface{}) Op {
+	return newBucketsOp(o.Buckets(v, startTime), o.Buckets(v, endTime), endTime,
+		[]Relation{GTE(flakeTimestampFieldName, startTime), LT(flakeTimestampFieldName, endTime)},
+		pointerToASlice)
+}
+
+func (o *multiKeyFlakeSeriesT) ListSince(v map[string]interface{}, id string, window time.Duration, pointerToASlice interface{}) Op {
+	codec, err := sinceCodec(o.Table())
+	if err != nil {
+		return errOp{err: err}
+	}
+	startTime, err := codec.Time(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	endTime := sinceEndTime(startTime, window)
+
+	return newBucketsOp(o.Buckets(v, startTime), o.Buckets(v, endTime), endTime,
+		[]Relation{GTE(flakeTimestampFieldName, startTime), LT(flakeTimestampFieldName, endTime), GT(o.idField, id)},
+		pointerToASlice)
+}
+
+func (o *multiKeyFlakeSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
+}
+
+func (o *multiKeyFlakeSeriesT) idCodec() IDCodec {
+	return tableIDCodec(o.Table())
+}
+
+func (o *multiKeyFlakeSeriesT) Buckets(v map[string]interface{}, start time.Time) Buckets {
+	return bucketIter{
+		v:         start,
+		bucketing: o.bucketing(),
+		field:     bucketFieldName,
+		invariant: o.Table().Where(o.ListOfEqualRelations(v)...)}
+}
+
+func (o *multiKeyFlakeSeriesT) Retention(ctx context.Context, v map[string]interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(v, start), cutoff)
+}
+
+func (o *multiKeyFlakeSeriesT) WithOptions(opt Options) MultiKeyFlakeSeriesTable {
+	result := &multiKeyFlakeSeriesT{
+		t:           o.Table().WithOptions(opt),
+		indexFields: o.indexFields,
+		idField:     o.idField,
+		bucketSize:  o.bucketSize,
+	}
+	reregister(o, result)
+	return result
+}
+
+func (o *multiKeyFlakeSeriesT) ListOfEqualRelations(fieldsToIndex map[string]interface{}) []Relation {
+	relations := make([]Relation, 0)
+
+	for _, field := range o.indexFields {
+		if value := fieldsToIndex[field]; value != nil && value != "" {
+			relations = append(relations, Eq(field, value))
+		}
+	}
+
+	return relations
+}
@@ -0,0 +1,23 @@
+package gocassa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiKeyFlakeSeriesTableStatements(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").MultiKeyFlakeSeriesTable("trip", []string{"TagA", "TagB"}, "Id", time.Hour, TripC{})
+	assert.Equal(t, "trip_multiKeyFlakeSeries_Id_1h0m0s", tbl.Name())
+
+	stmt, err := tbl.CreateStatement()
+	assert.NoError(t, err)
+	assert.Contains(t, stmt.Query(), "PRIMARY KEY ((taga, tagb, bucket), flake_created, id)")
+
+	id := timeToFlake(t, "2006 Jan 2 15:04:05")
+	stmt = tbl.Read(map[string]interface{}{"TagA": "A", "TagB": "B"}, id, &TripC{}).GenerateStatement()
+	assert.Equal(t, "SELECT id, taga, tagb, time, bucket, flake_created FROM ks1.trip_multiKeyFlakeSeries_Id_1h0m0s WHERE taga = ? AND tagb = ? AND bucket = ? AND flake_created = ? AND id = ?", stmt.Query())
+	assert.Equal(t, []interface{}{"A", "B"}, stmt.Values()[:2])
+	assert.Equal(t, id, stmt.Values()[4])
+}
@@ -1,6 +1,7 @@
 package gocassa
 
 import (
+	"context"
 	"time"
 )
 
@@ -21,6 +22,9 @@ func (o *multiKeyTimeSeriesT) CreateStatement() (Statement, error) { return o.Ta
 func (o *multiKeyTimeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *multiKeyTimeSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
 
 func (o *multiKeyTimeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -30,14 +34,14 @@ func (o *multiKeyTimeSeriesT) Set(v interface{}) Op {
 	if tim, ok := m[o.timeField].(time.Time); !ok {
 		panic("timeField is not actually a time.Time")
 	} else {
-		m[bucketFieldName] = bucket(tim, o.bucketSize)
+		m[bucketFieldName] = o.bucketing().Bucket(tim)
 	}
 	return o.Table().
 		Set(m)
 }
 
 func (o *multiKeyTimeSeriesT) Update(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, m map[string]interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	relations := make([]Relation, 0)
 	relations = append(relations, o.ListOfEqualRelations(v, id)...)
 	relations = append(relations, Eq(bucketFieldName, bucket))
@@ -48,8 +52,12 @@ func (o *multiKeyTimeSeriesT) Update(v map[string]interface{}, timeStamp time.Ti
 		Update(m)
 }
 
+func (o *multiKeyTimeSeriesT) UpdateStatic(v map[string]interface{}, timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(v, timeStamp).Filter().Update(m)
+}
+
 func (o *multiKeyTimeSeriesT) Delete(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	relations := make([]Relation, 0)
 	relations = append(relations, o.ListOfEqualRelations(v, id)...)
 	relations = append(relations, Eq(bucketFieldName, bucket))
@@ -61,7 +69,7 @@ func (o *multiKeyTimeSeriesT) Delete(v map[string]interface{}, timeStamp time.Ti
 }
 
 func (o *multiKeyTimeSeriesT) Read(v map[string]interface{}, timeStamp time.Time, id map[string]interface{}, pointer interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	relations := make([]Relation, 0)
 	relations = append(relations, o.ListOfEqualRelations(v, id)...)
 	relations = append(relations, Eq(bucketFieldName, bucket))
@@ -74,38 +82,37 @@ func (o *multiKeyTimeSeriesT) Read(v map[string]interface{}, timeStamp time.Time
 }
 
 func (o *multiKeyTimeSeriesT) List(v map[string]interface{}, startTime time.Time, endTime time.Time, pointerToASlice interface{}) Op {
-	buckets := []interface{}{}
-	for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
-		buckets = append(buckets, bucket.Bucket())
-	}
-
-	relations := make([]Relation, 0)
-	relations = append(relations, o.ListOfEqualRelations(v, nil)...)
-	relations = append(relations, In(bucketFieldName, buckets...))
-	relations = append(relations, GTE(o.timeField, startTime))
-	relations = append(relations, LTE(o.timeField, endTime))
+	return newBucketsOp(o.Buckets(v, startTime), o.Buckets(v, endTime), endTime,
+		[]Relation{GTE(o.timeField, startTime), LTE(o.timeField, endTime)},
+		pointerToASlice)
+}
 
-	return o.Table().
-		Where(relations...).
-		Read(pointerToASlice)
+func (o *multiKeyTimeSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
 }
 
 func (o *multiKeyTimeSeriesT) Buckets(v map[string]interface{}, start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		bucketing: o.bucketing(),
 		field:     bucketFieldName,
 		invariant: o.Table().Where(o.ListOfEqualRelations(v, nil)...)}
 }
 
+func (o *multiKeyTimeSeriesT) Retention(ctx context.Context, v map[string]interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(v, start), cutoff)
+}
+
 func (o *multiKeyTimeSeriesT) WithOptions(opt Options) MultiKeyTimeSeriesTable {
-	return &multiKeyTimeSeriesT{
+	result := &multiKeyTimeSeriesT{
 		t:           o.Table().WithOptions(opt),
 		indexFields: o.indexFields,
 		timeField:   o.timeField,
 		idFields:    o.idFields,
 		bucketSize:  o.bucketSize,
 	}
+	reregister(o, result)
+	return result
 }
 
 func (o *multiKeyTimeSeriesT) ListOfEqualRelations(fieldsToIndex, ids map[string]interface{}) []Relation {
@@ -0,0 +1,154 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+type multiKeyTimeUUIDSeriesT struct {
+	t           Table
+	indexFields []string
+	idField     string
+	bucketSize  time.Duration
+}
+
+func (o *multiKeyTimeUUIDSeriesT) Table() Table            { return o.t }
+func (o *multiKeyTimeUUIDSeriesT) Create() error           { return o.Table().Create() }
+func (o *multiKeyTimeUUIDSeriesT) CreateIfNotExist() error { return o.Table().CreateIfNotExist() }
+func (o *multiKeyTimeUUIDSeriesT) Name() string            { return o.Table().Name() }
+func (o *multiKeyTimeUUIDSeriesT) Recreate() error         { return o.Table().Recreate() }
+func (o *multiKeyTimeUUIDSeriesT) CreateStatement() (Statement, error) {
+	return o.Table().CreateStatement()
+}
+func (o *multiKeyTimeUUIDSeriesT) CreateIfNotExistStatement() (Statement, error) {
+	return o.Table().CreateIfNotExistStatement()
+}
+func (o *multiKeyTimeUUIDSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
+
+func (o *multiKeyTimeUUIDSeriesT) Set(v interface{}) Op {
+	m, ok := toMap(v)
+	if !ok {
+		panic("Can't set: not able to convert")
+	}
+	id, ok := m[o.idField].(gocql.UUID)
+	if !ok {
+		panic(fmt.Sprintf("Id field (%s) is not present or is not a gocql.UUID", o.idField))
+	}
+
+	timestamp, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	m[bucketFieldName] = o.bucketing().Bucket(timestamp)
+
+	return o.Table().Set(m)
+}
+
+func (o *multiKeyTimeUUIDSeriesT) Update(v map[string]interface{}, id gocql.UUID, m map[string]interface{}) Op {
+	timestamp, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	relations := o.ListOfEqualRelations(v)
+	relations = append(relations, Eq(bucketFieldName, o.bucketing().Bucket(timestamp)))
+	relations = append(relations, Eq(o.idField, id))
+
+	return o.Table().
+		Where(relations...).
+		Update(m)
+}
+
+func (o *multiKeyTimeUUIDSeriesT) UpdateStatic(v map[string]interface{}, timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(v, timeStamp).Filter().Update(m)
+}
+
+func (o *multiKeyTimeUUIDSeriesT) Delete(v map[string]interface{}, id gocql.UUID) Op {
+	timestamp, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	relations := o.ListOfEqualRelations(v)
+	relations = append(relations, Eq(bucketFieldName, o.bucketing().Bucket(timestamp)))
+	relations = append(relations, Eq(o.idField, id))
+
+	return o.Table().
+		Where(relations...).
+		Delete()
+}
+
+func (o *multiKeyTimeUUIDSeriesT) Read(v map[string]interface{}, id gocql.UUID, pointer interface{}) Op {
+	timestamp, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	relations := o.ListOfEqualRelations(v)
+	relations = append(relations, Eq(bucketFieldName, o.bucketing().Bucket(timestamp)))
+	relations = append(relations, Eq(o.idField, id))
+
+	return o.Table().
+		Where(relations...).
+		ReadOne(pointer)
+}
+
+func (o *multiKeyTimeUUIDSeriesT) List(v map[string]interface{}, startTime, endTime time.Time, pointerToASlice interface{}) Op {
+	return newBucketsOp(o.Buckets(v, startTime), o.Buckets(v, endTime), endTime,
+		[]Relation{GTE(o.idField, minTimeUUID(startTime)), LT(o.idField, minTimeUUID(endTime))},
+		pointerToASlice)
+}
+
+func (o *multiKeyTimeUUIDSeriesT) ListSince(v map[string]interface{}, id gocql.UUID, window time.Duration, pointerToASlice interface{}) Op {
+	startTime, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	endTime := sinceEndTime(startTime, window)
+
+	return newBucketsOp(o.Buckets(v, startTime), o.Buckets(v, endTime), endTime,
+		[]Relation{GT(o.idField, id), LT(o.idField, minTimeUUID(endTime))},
+		pointerToASlice)
+}
+
+func (o *multiKeyTimeUUIDSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
+}
+
+func (o *multiKeyTimeUUIDSeriesT) Buckets(v map[string]interface{}, start time.Time) Buckets {
+	return bucketIter{
+		v:         start,
+		bucketing: o.bucketing(),
+		field:     bucketFieldName,
+		invariant: o.Table().Where(o.ListOfEqualRelations(v)...)}
+}
+
+func (o *multiKeyTimeUUIDSeriesT) Retention(ctx context.Context, v map[string]interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(v, start), cutoff)
+}
+
+func (o *multiKeyTimeUUIDSeriesT) WithOptions(opt Options) MultiKeyTimeUUIDSeriesTable {
+	result := &multiKeyTimeUUIDSeriesT{
+		t:           o.Table().WithOptions(opt),
+		indexFields: o.indexFields,
+		idField:     o.idField,
+		bucketSize:  o.bucketSize,
+	}
+	reregister(o, result)
+	return result
+}
+
+func (o *multiKeyTimeUUIDSeriesT) ListOfEqualRelations(fieldsToIndex map[string]interface{}) []Relation {
+	relations := make([]Relation, 0)
+
+	for _, field := range o.indexFields {
+		if value := fieldsToIndex[field]; value != nil && value != "" {
+			relations = append(relations, Eq(field, value))
+		}
+	}
+
+	return relations
+}
@@ -1,5 +1,7 @@
 package gocassa
 
+import "fmt"
+
 type multimapMkT struct {
 	t               Table
 	fieldsToIndexBy []string
@@ -15,6 +17,9 @@ func (mm *multimapMkT) CreateStatement() (Statement, error) { return mm.Table().
 func (mm *multimapMkT) CreateIfNotExistStatement() (Statement, error) {
 	return mm.Table().CreateIfNotExistStatement()
 }
+func (mm *multimapMkT) CreateTypeStatements() ([]Statement, error) {
+	return mm.Table().CreateTypeStatements()
+}
 
 func (mm *multimapMkT) Update(field, id map[string]interface{}, m map[string]interface{}) Op {
 	return mm.Table().
@@ -22,6 +27,12 @@ func (mm *multimapMkT) Update(field, id map[string]interface{}, m map[string]int
 		Update(m)
 }
 
+func (mm *multimapMkT) UpdateStatic(field map[string]interface{}, m map[string]interface{}) Op {
+	return mm.Table().
+		Where(mm.ListOfEqualRelations(field, nil)...).
+		Update(m)
+}
+
 func (mm *multimapMkT) Set(v interface{}) Op {
 	return mm.Table().
 		Set(v)
@@ -69,12 +80,68 @@ func (mm *multimapMkT) List(field, startId map[string]interface{}, limit int, po
 		Read(pointerToASlice)
 }
 
+// MkIDRange is like IDRange for the composite ids of MultimapMkTable.ListRange. Ids are
+// compared lexicographically in the order of the clustering columns, and may only set the
+// first few of them, for example to list the rows from the first one with a given value
+// of the first clustering column.
+type MkIDRange struct {
+	Start          map[string]interface{}
+	ExclusiveStart bool
+	End            map[string]interface{}
+	Descending     bool
+}
+
+func (mm *multimapMkT) ListRange(v map[string]interface{}, r MkIDRange, limit int, pointerToASlice interface{}) Op {
+	startFields, startValues, err := mm.idPrefix(r.Start)
+	if err != nil {
+		return errOp{err: err}
+	}
+	endFields, endValues, err := mm.idPrefix(r.End)
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	tuple := len(startFields) > 1 || len(endFields) > 1
+	rels := mm.ListOfEqualRelations(v, nil)
+	if len(startFields) > 0 {
+		rels = append(rels, rangeRelation(startFields, startValues, true, !r.ExclusiveStart, r.Descending, tuple))
+	}
+	if len(endFields) > 0 {
+		rels = append(rels, rangeRelation(endFields, endValues, false, true, r.Descending, tuple))
+	}
+	return mm.Table().
+		WithOptions(rangeOptions(mm.idField, limit, r.Descending)).
+		Where(rels...).
+		Read(pointerToASlice)
+}
+
+// idPrefix returns the leading clustering columns set in the given id, and
+// their values. Clustering columns can't be set after one which is not.
+func (mm *multimapMkT) idPrefix(id map[string]interface{}) ([]string, []interface{}, error) {
+	fields := []string{}
+	values := []interface{}{}
+	for _, field := range mm.idField {
+		value := id[field]
+		if value == nil || value == "" {
+			continue
+		}
+		if mm.idField[len(fields)] != field {
+			return nil, nil, fmt.Errorf("Id field (%s) is set after a field which is not", field)
+		}
+		fields = append(fields, field)
+		values = append(values, value)
+	}
+	return fields, values, nil
+}
+
 func (mm *multimapMkT) WithOptions(o Options) MultimapMkTable {
-	return &multimapMkT{
+	result := &multimapMkT{
 		t:               mm.Table().WithOptions(o),
 		fieldsToIndexBy: mm.fieldsToIndexBy,
 		idField:         mm.idField,
 	}
+	reregister(mm, result)
+	return result
 }
 
 func (mm *multimapMkT) ListOfEqualRelations(fieldsToIndex, ids map[string]interface{}) []Relation {
@@ -15,6 +15,9 @@ func (mm *multimapT) CreateStatement() (Statement, error) { return mm.Table().Cr
 func (mm *multimapT) CreateIfNotExistStatement() (Statement, error) {
 	return mm.Table().CreateIfNotExistStatement()
 }
+func (mm *multimapT) CreateTypeStatements() ([]Statement, error) {
+	return mm.Table().CreateTypeStatements()
+}
 
 func (mm *multimapT) Update(field, id interface{}, m map[string]interface{}) Op {
 	return mm.Table().
@@ -23,6 +26,12 @@ func (mm *multimapT) Update(field, id interface{}, m map[string]interface{}) Op
 		Update(m)
 }
 
+func (mm *multimapT) UpdateStatic(field interface{}, m map[string]interface{}) Op {
+	return mm.Table().
+		Where(Eq(mm.fieldToIndexBy, field)).
+		Update(m)
+}
+
 func (mm *multimapT) Set(v interface{}) Op {
 	return mm.Table().
 		Set(v)
@@ -48,10 +57,7 @@ func (mm *multimapT) Read(field, id, pointer interface{}) Op {
 }
 
 func (mm *multimapT) MultiRead(field interface{}, ids []interface{}, pointerToASlice interface{}) Op {
-	return mm.Table().
-		Where(Eq(mm.fieldToIndexBy, field),
-			In(mm.idField, ids...)).
-		Read(pointerToASlice)
+	return newMultiReadOp(mm.Table(), []Relation{Eq(mm.fieldToIndexBy, field)}, mm.idField, ids, pointerToASlice)
 }
 
 func (mm *multimapT) List(field, startId interface{}, limit int, pointerToASlice interface{}) Op {
@@ -67,10 +73,85 @@ func (mm *multimapT) List(field, startId interface{}, limit int, pointerToASlice
 		Read(pointerToASlice)
 }
 
+// IDRange bounds the ids of the rows listed by MultimapTable.ListRange
+type IDRange struct {
+	// Start is the id of the first row listed, or nil to list from the first row of the
+	// partition (or its last row if Descending)
+	Start interface{}
+	// ExclusiveStart leaves the row of Start out, to list the rows after the last row of
+	// the previous page
+	ExclusiveStart bool
+	// End is the id of the last row listed, or nil to list up to the end of the partition
+	End interface{}
+	// Descending lists the rows in descending order of their id, from Start down to End
+	Descending bool
+}
+
+func (mm *multimapT) ListRange(field interface{}, r IDRange, limit int, pointerToASlice interface{}) Op {
+	rels := []Relation{Eq(mm.fieldToIndexBy, field)}
+	idFields := []string{mm.idField}
+	if r.Start != nil {
+		rels = append(rels, rangeRelation(idFields, []interface{}{r.Start}, true, !r.ExclusiveStart, r.Descending, false))
+	}
+	if r.End != nil {
+		rels = append(rels, rangeRelation(idFields, []interface{}{r.End}, false, true, r.Descending, false))
+	}
+	return mm.Table().
+		WithOptions(rangeOptions(idFields, limit, r.Descending)).
+		Where(rels...).
+		Read(pointerToASlice)
+}
+
+// rangeRelation returns the relation bounding a range of clustering columns,
+// which is a tuple relation if tuple is set. Both bounds of a range must be
+// tuple relations if either spans several columns, as Cassandra doesn't mix
+// them with single column relations. The start of an ascending range is its
+// lower bound, and the start of a descending one its upper bound.
+func rangeRelation(fields []string, values []interface{}, start, inclusive, descending, tuple bool) Relation {
+	lower := start != descending
+	if !tuple {
+		switch {
+		case lower && inclusive:
+			return GTE(fields[0], values[0])
+		case lower:
+			return GT(fields[0], values[0])
+		case inclusive:
+			return LTE(fields[0], values[0])
+		}
+		return LT(fields[0], values[0])
+	}
+	switch {
+	case lower && inclusive:
+		return TupleGTE(fields, values...)
+	case lower:
+		return TupleGT(fields, values...)
+	case inclusive:
+		return TupleLTE(fields, values...)
+	}
+	return TupleLT(fields, values...)
+}
+
+// rangeOptions returns the options listing up to limit rows of a range of
+// clustering columns, in descending order if descending. The order is always
+// set, as the clustering order of the table might be descending.
+func rangeOptions(fields []string, limit int, descending bool) Options {
+	opts := Options{Limit: limit}
+	direction := ASC
+	if descending {
+		direction = DESC
+	}
+	for _, field := range fields {
+		opts.ClusteringOrder = append(opts.ClusteringOrder, ClusteringOrderColumn{Direction: direction, Column: field})
+	}
+	return opts
+}
+
 func (mm *multimapT) WithOptions(o Options) MultimapTable {
-	return &multimapT{
+	result := &multimapT{
 		t:              mm.Table().WithOptions(o),
 		fieldToIndexBy: mm.fieldToIndexBy,
 		idField:        mm.idField,
 	}
+	reregister(mm, result)
+	return result
 }
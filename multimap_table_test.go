@@ -3,6 +3,8 @@ package gocassa
 import (
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type Customer2 struct {
@@ -167,3 +169,50 @@ func TestMultimapTableMultiListOrder(t *testing.T) {
 		t.Fatalf("Expected to find joe, got %v", (customers)[1])
 	}
 }
+
+func TestMultimapTableListRangeStatements(t *testing.T) {
+	ks := NewConnection(nil).KeySpace("ks1")
+	tbl := ks.MultimapTable("customer", "Tag", "Id", Customer2{})
+	list := []Customer2{}
+
+	stmt := tbl.ListRange("A", IDRange{Start: "33", ExclusiveStart: true, End: "66"}, 10, &list).GenerateStatement()
+	assert.Equal(t, "SELECT id, name, tag FROM ks1.customer_multimap_Tag_Id WHERE tag = ? AND id > ? AND id <= ? ORDER BY id ASC LIMIT ?", stmt.Query())
+	assert.Equal(t, []interface{}{"A", "33", "66", 10}, stmt.Values())
+
+	stmt = tbl.ListRange("A", IDRange{Start: "66", End: "33", Descending: true}, 0, &list).GenerateStatement()
+	assert.Equal(t, "SELECT id, name, tag FROM ks1.customer_multimap_Tag_Id WHERE tag = ? AND id <= ? AND id >= ? ORDER BY id DESC", stmt.Query())
+	assert.Equal(t, []interface{}{"A", "66", "33"}, stmt.Values())
+
+	mkTbl := ks.MultimapMultiKeyTable("store", StorePK, StoreIndex, Store{})
+	stores := []Store{}
+	stmt = mkTbl.ListRange(map[string]interface{}{CityKey: "London"}, MkIDRange{
+		Start:          map[string]interface{}{ManagerKey: "Jane", IdKey: "1"},
+		ExclusiveStart: true,
+		End:            map[string]interface{}{ManagerKey: "Joe"},
+	}, 10, &stores).GenerateStatement()
+	assert.Equal(t, "SELECT address, city, id, manager FROM ks1.store_multimapMk WHERE city = ? AND (manager, id) > (?, ?) AND (manager) <= (?) ORDER BY manager ASC, id ASC LIMIT ?", stmt.Query())
+	assert.Equal(t, []interface{}{"London", "Jane", "1", "Joe", 10}, stmt.Values())
+
+	// Bounds on a single column stay single column relations
+	stmt = mkTbl.ListRange(map[string]interface{}{CityKey: "London"}, MkIDRange{
+		Start: map[string]interface{}{ManagerKey: "Jane"},
+		End:   map[string]interface{}{ManagerKey: "Joe"},
+	}, 0, &stores).GenerateStatement()
+	assert.Equal(t, "SELECT address, city, id, manager FROM ks1.store_multimapMk WHERE city = ? AND manager >= ? AND manager <= ? ORDER BY manager ASC, id ASC", stmt.Query())
+
+	err := mkTbl.ListRange(nil, MkIDRange{Start: map[string]interface{}{IdKey: "1"}}, 0, &stores).Run()
+	assert.EqualError(t, err, "Id field (Id) is set after a field which is not")
+}
+
+func TestMultimapTableListRangeDescendingClusteringOrder(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").MultimapTable("customer", "Tag", "Id", Customer2{}).
+		WithOptions(Options{ClusteringOrder: []ClusteringOrderColumn{{Direction: DESC, Column: "Id"}}})
+	list := []Customer2{}
+
+	// Ascending ranges don't take the order of the table
+	stmt := tbl.ListRange("A", IDRange{Start: "33", End: "66"}, 0, &list).GenerateStatement()
+	assert.Equal(t, "SELECT id, name, tag FROM ks1.customer_multimap_Tag_Id WHERE tag = ? AND id >= ? AND id <= ? ORDER BY id ASC", stmt.Query())
+
+	stmt = tbl.ListRange("A", IDRange{Start: "66", End: "33", Descending: true}, 0, &list).GenerateStatement()
+	assert.Equal(t, "SELECT id, name, tag FROM ks1.customer_multimap_Tag_Id WHERE tag = ? AND id <= ? AND id >= ? ORDER BY id DESC", stmt.Query())
+}
@@ -0,0 +1,306 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+
+	"gopkg.in/inf.v0"
+)
+
+const (
+	// defaultMultiReadChunkSize is the number of keys ParallelMultiReadQuery
+	// reads with a query if Options.MultiReadChunkSize isn't set
+	defaultMultiReadChunkSize = 1
+	// defaultMultiReadParallelism is the number of queries ParallelMultiReadQuery
+	// runs at a time if Options.MultiReadParallelism isn't set
+	defaultMultiReadParallelism = 4
+)
+
+// KeysNotFoundError is returned by MultiReads with ParallelMultiReadQuery and
+// the MultiReadMissingKeys option when some of the keys have no rows, along
+// with the rows of the other keys.
+type KeysNotFoundError struct {
+	// Keys are the keys with no rows, in the order they were requested
+	Keys []interface{}
+}
+
+func (e KeysNotFoundError) Error() string {
+	return fmt.Sprintf("No rows returned for keys %v", e.Keys)
+}
+
+// multiReadOp reads the rows of a list of keys of a field. Depending on the
+// MultiReadQuery option, it reads all keys at once with an IN relation on the
+// field, or splits them into chunks which are read concurrently and whose rows
+// are merged in the order of the keys.
+type multiReadOp struct {
+	options   Options
+	t         Table
+	relations []Relation // relations applied to every key, such as the partition of a multimap
+	field     string
+	keys      []interface{}
+	result    interface{}
+}
+
+func newMultiReadOp(t Table, relations []Relation, field string, keys []interface{}, pointerToASlice interface{}) *multiReadOp {
+	return &multiReadOp{
+		t:         t,
+		relations: relations,
+		field:     field,
+		keys:      keys,
+		result:    pointerToASlice,
+	}
+}
+
+// chunks splits the keys into the chunks read by each query, skipping
+// repeated keys
+func (o *multiReadOp) chunks(opt Options) [][]interface{} {
+	size := opt.MultiReadChunkSize
+	if size <= 0 {
+		size = defaultMultiReadChunkSize
+	}
+	position := o.keyPositions()
+	chunks := [][]interface{}{}
+	chunk := []interface{}{}
+	for i, key := range o.keys {
+		if position(convertToPrimitive(key)) != i {
+			continue
+		}
+		chunk = append(chunk, key)
+		if len(chunk) == size {
+			chunks = append(chunks, chunk)
+			chunk = []interface{}{}
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// keyPositions returns a function returning the position of the first key
+// with the given primitive value, or the number of keys if there is none
+func (o *multiReadOp) keyPositions() func(key interface{}) int {
+	// Keys are looked up by their primitive value, or else compared one by one
+	index := map[interface{}]int{}
+	for i := len(o.keys) - 1; i >= 0; i-- {
+		if key := convertToPrimitive(o.keys[i]); hashableKey(key) {
+			index[key] = i
+		}
+	}
+	return func(key interface{}) int {
+		if hashableKey(key) {
+			if i, ok := index[key]; ok {
+				return i
+			}
+		}
+		for i, k := range o.keys {
+			if builtinEquals(convertToPrimitive(k), key) {
+				return i
+			}
+		}
+		return len(o.keys)
+	}
+}
+
+// chunkOp returns the op reading the given keys, with an equality relation if
+// there is a single one
+func (o *multiReadOp) chunkOp(keys []interface{}, result interface{}) Op {
+	rels := append([]Relation{}, o.relations...)
+	if len(keys) == 1 {
+		rels = append(rels, Eq(o.field, keys[0]))
+	} else {
+		rels = append(rels, In(o.field, keys...))
+	}
+	return o.t.Where(rels...).Read(result).WithOptions(o.options)
+}
+
+// inOp returns the op reading all keys with a single query
+func (o *multiReadOp) inOp() Op {
+	rels := append(append([]Relation{}, o.relations...), In(o.field, o.keys...))
+	return o.t.Where(rels...).Read(o.result).WithOptions(o.options)
+}
+
+// firstOp returns the op reading all keys if they are read with a single
+// query, or else the op reading the first chunk of keys
+func (o *multiReadOp) firstOp() Op {
+	opt := o.t.Options().Merge(o.options)
+	if opt.MultiReadQuery != ParallelMultiReadQuery || len(o.keys) == 0 {
+		return o.inOp()
+	}
+	return o.chunkOp(o.chunks(opt)[0], o.result)
+}
+
+func (o *multiReadOp) Run() error {
+	if err := o.Preflight(); err != nil {
+		return err
+	}
+
+	opt := o.t.Options().Merge(o.options)
+	if opt.MultiReadQuery != ParallelMultiReadQuery {
+		return o.inOp().Run()
+	}
+
+	parallelism := opt.MultiReadParallelism
+	if parallelism <= 0 {
+		parallelism = defaultMultiReadParallelism
+	}
+	slice := reflect.ValueOf(o.result).Elem()
+	pages, err := o.readChunks(o.chunks(opt), slice.Type(), parallelism)
+	if err != nil {
+		return err
+	}
+
+	rows := reflect.MakeSlice(slice.Type(), 0, 0)
+	for _, page := range pages {
+		rows = reflect.AppendSlice(rows, page)
+	}
+	rows, missing, err := o.orderRows(rows)
+	if err != nil {
+		return err
+	}
+	slice.Set(rows)
+	if len(missing) > 0 && opt.MultiReadMissingKeys {
+		return KeysNotFoundError{Keys: missing}
+	}
+	return nil
+}
+
+// readChunks reads the given chunks of keys, up to parallelism of them at a
+// time, and returns their rows in the order of the chunks
+func (o *multiReadOp) readChunks(chunks [][]interface{}, sliceType reflect.Type, parallelism int) ([]reflect.Value, error) {
+	pages := make([]reflect.Value, len(chunks))
+	errs := make([]error, len(chunks))
+	next := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < parallelism && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				page := reflect.New(sliceType)
+				errs[i] = o.chunkOp(chunks[i], page.Interface()).Run()
+				pages[i] = page.Elem()
+			}
+		}()
+	}
+
+	var err error
+	for i := range chunks {
+		if ctx := o.options.Context; ctx != nil && ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+// orderRows sorts rows in the order of the keys they were read for, and
+// returns the keys which have no rows
+func (o *multiReadOp) orderRows(rows reflect.Value) (reflect.Value, []interface{}, error) {
+	position := o.keyPositions()
+	positions := make([]int, rows.Len())
+	found := make([]bool, len(o.keys)+1)
+	for i := range positions {
+		m, ok := toMap(rows.Index(i).Interface())
+		if !ok {
+			return rows, nil, fmt.Errorf("Can't sort rows of type %v", rows.Type().Elem())
+		}
+		positions[i] = position(convertToPrimitive(m[o.field]))
+		found[positions[i]] = true
+	}
+
+	order := make([]int, len(positions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return positions[order[i]] < positions[order[j]]
+	})
+	sorted := reflect.MakeSlice(rows.Type(), 0, len(order))
+	for _, i := range order {
+		sorted = reflect.Append(sorted, rows.Index(i))
+	}
+
+	missing := []interface{}{}
+	for i, key := range o.keys {
+		// Repeated keys are only reported once
+		if !found[i] && position(convertToPrimitive(key)) == i {
+			missing = append(missing, key)
+		}
+	}
+	return sorted, missing, nil
+}
+
+// hashableKey returns whether a key can be looked up in a map, which is not
+// the case of uncomparable values or of the pointers of arbitrary precision
+// numbers, which are compared by their value
+func hashableKey(key interface{}) bool {
+	switch key.(type) {
+	case nil, *big.Int, *inf.Dec:
+		return false
+	}
+	return reflect.TypeOf(key).Comparable()
+}
+
+func (o *multiReadOp) RunWithContext(ctx context.Context) error {
+	return o.WithOptions(Options{Context: ctx}).Run()
+}
+
+func (o *multiReadOp) RunAtomically() error {
+	return o.Run()
+}
+
+func (o *multiReadOp) RunLoggedBatchWithContext(ctx context.Context) error {
+	return o.WithOptions(Options{Context: ctx}).Run()
+}
+
+func (o *multiReadOp) RunAtomicallyWithContext(ctx context.Context) error {
+	return o.RunLoggedBatchWithContext(ctx)
+}
+
+func (o *multiReadOp) Add(additions ...Op) Op {
+	return multiOp{o}.Add(additions...)
+}
+
+func (o *multiReadOp) Options() Options {
+	return o.options
+}
+
+func (o *multiReadOp) WithOptions(opts Options) Op {
+	result := *o
+	result.options = o.options.Merge(opts)
+	return &result
+}
+
+func (o *multiReadOp) Preflight() error {
+	if v := reflect.ValueOf(o.result); v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("MultiRead can only read into a pointer to a slice, got %T", o.result)
+	}
+	return nil
+}
+
+// GenerateStatement generates the statement reading all keys if they are read
+// with a single query, or else the statement reading the first chunk of keys
+func (o *multiReadOp) GenerateStatement() Statement {
+	return o.firstOp().GenerateStatement()
+}
+
+func (o *multiReadOp) QueryExecutor() QueryExecutor {
+	return o.firstOp().QueryExecutor()
+}
@@ -1,6 +1,7 @@
 package gocassa
 
 import (
+	"context"
 	"time"
 )
 
@@ -21,6 +22,9 @@ func (o *multiTimeSeriesT) CreateStatement() (Statement, error) { return o.Table
 func (o *multiTimeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *multiTimeSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
 
 func (o *multiTimeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -30,14 +34,14 @@ func (o *multiTimeSeriesT) Set(v interface{}) Op {
 	if tim, ok := m[o.timeField].(time.Time); !ok {
 		panic("timeField is not actually a time.Time")
 	} else {
-		m[bucketFieldName] = bucket(tim, o.bucketSize)
+		m[bucketFieldName] = o.bucketing().Bucket(tim)
 	}
 	return o.Table().
 		Set(m)
 }
 
 func (o *multiTimeSeriesT) Update(v interface{}, timeStamp time.Time, id interface{}, m map[string]interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(o.indexField, v),
 			Eq(bucketFieldName, bucket),
@@ -46,8 +50,12 @@ func (o *multiTimeSeriesT) Update(v interface{}, timeStamp time.Time, id interfa
 		Update(m)
 }
 
+func (o *multiTimeSeriesT) UpdateStatic(v interface{}, timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(v, timeStamp).Filter().Update(m)
+}
+
 func (o *multiTimeSeriesT) Delete(v interface{}, timeStamp time.Time, id interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(o.indexField, v),
 			Eq(bucketFieldName, bucket),
@@ -57,7 +65,7 @@ func (o *multiTimeSeriesT) Delete(v interface{}, timeStamp time.Time, id interfa
 }
 
 func (o *multiTimeSeriesT) Read(v interface{}, timeStamp time.Time, id, pointer interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(o.indexField, v),
 			Eq(bucketFieldName, bucket),
@@ -68,32 +76,39 @@ func (o *multiTimeSeriesT) Read(v interface{}, timeStamp time.Time, id, pointer
 }
 
 func (o *multiTimeSeriesT) List(v interface{}, startTime time.Time, endTime time.Time, pointerToASlice interface{}) Op {
-	buckets := []interface{}{}
-	for bucket := o.Buckets(v, startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
-		buckets = append(buckets, bucket.Bucket())
-	}
-	return o.Table().
-		Where(Eq(o.indexField, v),
-			In(bucketFieldName, buckets...),
-			GTE(o.timeField, startTime),
-			LTE(o.timeField, endTime)).
-		Read(pointerToASlice)
+	return newBucketsOp(o.Buckets(v, startTime), o.Buckets(v, endTime), endTime,
+		[]Relation{GTE(o.timeField, startTime), LTE(o.timeField, endTime)},
+		pointerToASlice)
+}
+
+func (o *multiTimeSeriesT) ListLatest(v interface{}, start, before time.Time, limit int, pointerToASlice interface{}) Op {
+	return newLatestOp(o.Buckets(v, start), o.Buckets(v, before), o.timeField, start, before, limit, pointerToASlice)
+}
+
+func (o *multiTimeSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
 }
 
 func (o *multiTimeSeriesT) Buckets(v interface{}, start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		bucketing: o.bucketing(),
 		field:     bucketFieldName,
 		invariant: o.Table().Where(Eq(o.indexField, v))}
 }
 
+func (o *multiTimeSeriesT) Retention(ctx context.Context, v interface{}, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(v, start), cutoff)
+}
+
 func (o *multiTimeSeriesT) WithOptions(opt Options) MultiTimeSeriesTable {
-	return &multiTimeSeriesT{
+	result := &multiTimeSeriesT{
 		t:          o.Table().WithOptions(opt),
 		indexField: o.indexField,
 		timeField:  o.timeField,
 		idField:    o.idField,
 		bucketSize: o.bucketSize,
 	}
+	reregister(o, result)
+	return result
 }
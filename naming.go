@@ -0,0 +1,104 @@
+package gocassa
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxTableNameLength is the maximum length of a table name in Cassandra
+const maxTableNameLength = 48
+
+var validTableName = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// TableNaming is the strategy used to name the tables created by a keyspace
+// from the name prefix, recipe and fields of a table.
+type TableNaming int
+
+const (
+	// LegacyTableNames names tables as gocassa always has, for example
+	// "sale_multiTimeSeries_SellerId_Created_Id_24h0m0s". These names can be
+	// too long for Cassandra, in which case creating the table fails. This is
+	// the default so that existing deployments keep using their tables.
+	LegacyTableNames TableNaming = iota
+	// SafeTableNames names tables like LegacyTableNames, but formats bucket
+	// sizes compactly ("24h" rather than "24h0m0s"), replaces characters which
+	// aren't allowed in table names with underscores and shortens names which
+	// are longer than 48 characters, replacing their end with a hash of the
+	// full name. See KeySpace.TableNameMapping for the resulting names.
+	SafeTableNames
+)
+
+// tableName builds the name of a table from a format and its arguments using
+// the naming strategy of the keyspace. Durations are formatted according to
+// the strategy, so should be passed as time.Duration.
+func (k *k) tableName(format string, args ...interface{}) string {
+	legacy := fmt.Sprintf(format, args...)
+	if k.naming != SafeTableNames {
+		return legacy
+	}
+
+	safeArgs := make([]interface{}, len(args))
+	for i, arg := range args {
+		if d, ok := arg.(time.Duration); ok {
+			arg = compactDuration(d)
+		}
+		safeArgs[i] = arg
+	}
+	name := safeTableName(fmt.Sprintf(format, safeArgs...))
+	k.registry.rename(legacy, name)
+	return name
+}
+
+// safeTableName replaces invalid characters in the name and shortens it to
+// fit the maximum length. Shortened names end with a hash of the full name, so
+// names which share a long prefix remain distinct.
+func safeTableName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	if len(name) <= maxTableNameLength {
+		return name
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	suffix := fmt.Sprintf("_%08x", h.Sum32())
+	return name[:maxTableNameLength-len(suffix)] + suffix
+}
+
+// compactDuration formats a duration using its largest whole unit, for example
+// "24h" or "90m"
+func compactDuration(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "0s"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d%time.Second == 0:
+		return fmt.Sprintf("%ds", d/time.Second)
+	case d%time.Millisecond == 0:
+		return fmt.Sprintf("%dms", d/time.Millisecond)
+	}
+	return fmt.Sprintf("%dns", d.Nanoseconds())
+}
+
+// validateTableName returns an error if Cassandra would reject the name
+func validateTableName(name string) error {
+	switch {
+	case len(name) > maxTableNameLength:
+		return fmt.Errorf("Table name %q is %v characters long, Cassandra allows at most %v. "+
+			"Use SafeTableNames or set Options.TableName to shorten it", name, len(name), maxTableNameLength)
+	case !validTableName.MatchString(name):
+		return fmt.Errorf("Table name %q may only contain letters, digits and underscores. "+
+			"Use SafeTableNames or set Options.TableName to change it", name)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package gocassa
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactDuration(t *testing.T) {
+	assert.Equal(t, "24h", compactDuration(24*time.Hour))
+	assert.Equal(t, "90m", compactDuration(90*time.Minute))
+	assert.Equal(t, "45s", compactDuration(45*time.Second))
+	assert.Equal(t, "1500ms", compactDuration(1500*time.Millisecond))
+	assert.Equal(t, "10ns", compactDuration(10))
+	assert.Equal(t, "0s", compactDuration(0))
+}
+
+func TestSafeTableName(t *testing.T) {
+	assert.Equal(t, "sale_map_Id", safeTableName("sale_map_Id"))
+	assert.Equal(t, "sale_map_Seller_Id", safeTableName("sale_map_Seller.Id"))
+
+	long := "somelongishtablename_multiTimeSeries_StartTime_Created_Id_24h"
+	name := safeTableName(long)
+	assert.Len(t, name, maxTableNameLength)
+	assert.True(t, strings.HasPrefix(name, long[:30]))
+	assert.Equal(t, name, safeTableName(long))
+	assert.NotEqual(t, name, safeTableName(long+"2"))
+	assert.NoError(t, validateTableName(name))
+}
+
+func TestTableNaming(t *testing.T) {
+	for _, ks := range []KeySpace{NewConnection(nil).KeySpace("ks1"), NewMockKeySpace()} {
+		legacy := ks.MultiTimeSeriesTable("somelongishtablename", "SellerId", "Created", "Id", 24*time.Hour, typedSale{})
+		assert.Equal(t, "somelongishtablename_multiTimeSeries_SellerId_Created_Id_24h0m0s", legacy.Name())
+		assert.Empty(t, ks.TableNameMapping())
+
+		ks.SetTableNaming(SafeTableNames)
+		short := ks.TimeSeriesTable("sale", "Created", "Id", 24*time.Hour, typedSale{})
+		assert.Equal(t, "sale_timeSeries_Created_Id_24h", short.Name())
+		safe := ks.MultiTimeSeriesTable("somelongishtablename", "SellerId", "Created", "Id", 24*time.Hour, typedSale{})
+		assert.Len(t, safe.Name(), maxTableNameLength)
+		assert.Equal(t, map[string]string{
+			"sale_timeSeries_Created_Id_24h0m0s":                               "sale_timeSeries_Created_Id_24h",
+			"somelongishtablename_multiTimeSeries_SellerId_Created_Id_24h0m0s": safe.Name(),
+		}, ks.TableNameMapping())
+	}
+}
+
+func TestTableNameValidation(t *testing.T) {
+	for _, ks := range []KeySpace{NewConnection(nil).KeySpace("ks1"), NewMockKeySpace()} {
+		tbl := ks.MultiTimeSeriesTable("somelongishtablename", "SellerId", "Created", "Id", 24*time.Hour, typedSale{})
+		_, err := tbl.CreateStatement()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at most 48")
+
+		tbl = tbl.WithOptions(Options{TableName: "somelongishtablename_mts_24h"})
+		_, err = tbl.CreateStatement()
+		assert.NoError(t, err)
+	}
+
+	mockTbl := NewMockKeySpace().MapTable("sale", "Id", typedSale{}).WithOptions(Options{TableName: "sale-by-id"})
+	assert.Error(t, mockTbl.Create())
+
+	// No statement runs for a table with an invalid name, not even the ones
+	// creating its types
+	qe := &OptionCheckingQE{opts: &Options{}}
+	tbl := NewConnection(qe).KeySpace("ks1").MapTable("customer", "Id", customerWithAddress{}).
+		WithOptions(Options{TableName: "customer-by-id"})
+	assert.Error(t, tbl.Create())
+	assert.Error(t, tbl.CreateIfNotExist())
+	assert.Error(t, tbl.Recreate())
+	assert.Nil(t, qe.stmt)
+}
@@ -20,7 +20,17 @@ type singleOp struct {
 	opType  uint8
 	result  interface{}
 	m       map[string]interface{} // map for updates, sets etc
-	qe      QueryExecutor
+	columns []DeleteColumn         // columns for partial deletes
+	// aggregate and aggregateField select the aggregate of a field instead
+	// of the rows for reads, and distinct selects the distinct partition keys
+	aggregate      AggregateFunc
+	aggregateField string
+	distinct       bool
+	// json is the row to insert for JSON inserts, and readJSON selects the
+	// rows as JSON for reads
+	json     []byte
+	readJSON bool
+	qe       QueryExecutor
 }
 
 func (o *singleOp) Options() Options {
@@ -29,12 +39,18 @@ func (o *singleOp) Options() Options {
 
 func (o *singleOp) WithOptions(opts Options) Op {
 	return &singleOp{
-		options: o.options.Merge(opts),
-		f:       o.f,
-		opType:  o.opType,
-		result:  o.result,
-		m:       o.m,
-		qe:      o.qe}
+		options:        o.options.Merge(opts),
+		f:              o.f,
+		opType:         o.opType,
+		result:         o.result,
+		m:              o.m,
+		columns:        o.columns,
+		aggregate:      o.aggregate,
+		aggregateField: o.aggregateField,
+		distinct:       o.distinct,
+		json:           o.json,
+		readJSON:       o.readJSON,
+		qe:             o.qe}
 }
 
 func (o *singleOp) Add(additions ...Op) Op {
@@ -59,6 +75,11 @@ func (o *singleOp) Run() error {
 	case readOpType, singleReadOpType:
 		stmt := o.generateSelect(o.options)
 		scanner := NewScanner(stmt, o.result)
+		if o.aggregate != "" {
+			scanner = newValueScanner(o.result)
+		} else if o.readJSON {
+			scanner = newJSONScanner(o.result)
+		}
 		err = o.qe.QueryWithOptions(o.options, stmt, scanner)
 		if err != nil {
 			o.qe.IncrementPrometheusCounterError("read")
@@ -129,47 +150,70 @@ func (o *singleOp) QueryExecutor() QueryExecutor {
 
 func (o *singleOp) generateSelect(opt Options) SelectStatement {
 	mopt := o.f.t.options.Merge(opt)
-	return SelectStatement{
+	stmt := SelectStatement{
 		keyspace:       o.f.t.keySpace.name,
-		table:          o.f.t.Name(),
-		fields:         o.f.t.generateFieldList(mopt.Select),
+		table:          o.f.t.cqlName(mopt.CaseSensitive),
+		fields:         o.f.t.generateFieldList(mopt.Select, mopt.CaseSensitive),
 		where:          o.f.rs,
 		order:          mopt.ClusteringOrder,
 		limit:          mopt.Limit,
 		allowFiltering: mopt.AllowFiltering,
 		keys:           o.f.t.info.keys,
+		caseSensitive:  mopt.CaseSensitive,
+	}
+	switch {
+	case o.aggregate != "":
+		// Aggregates return a single row, so the clustering order and limit
+		// don't apply
+		stmt.fields = []string{o.aggregateField}
+		stmt.aggregate = o.aggregate
+		stmt.order = nil
+		stmt.limit = 0
+	case o.distinct:
+		stmt.fields = o.f.t.info.keys.PartitionKeys
+		stmt.distinct = true
+		stmt.order = nil
 	}
+	stmt.json = o.readJSON
+	return stmt
 }
 
 func (o *singleOp) generateInsert(opt Options) InsertStatement {
 	mopt := o.f.t.options.Merge(opt)
 	return InsertStatement{
-		keyspace: o.f.t.keySpace.name,
-		table:    o.f.t.Name(),
-		fieldMap: o.m,
-		ttl:      mopt.TTL,
-		keys:     o.f.t.info.keys,
+		keyspace:      o.f.t.keySpace.name,
+		table:         o.f.t.cqlName(mopt.CaseSensitive),
+		fieldMap:      o.m,
+		json:          o.json,
+		defaultUnset:  mopt.DefaultUnset,
+		ttl:           mopt.TTL,
+		keys:          o.f.t.info.keys,
+		caseSensitive: mopt.CaseSensitive,
 	}
 }
 
 func (o *singleOp) generateUpdate(opt Options) UpdateStatement {
 	mopt := o.f.t.options.Merge(opt)
 	return UpdateStatement{
-		keyspace: o.f.t.keySpace.name,
-		table:    o.f.t.Name(),
-		fieldMap: o.m,
-		where:    o.f.rs,
-		ttl:      mopt.TTL,
-		keys:     o.f.t.info.keys,
+		keyspace:      o.f.t.keySpace.name,
+		table:         o.f.t.cqlName(mopt.CaseSensitive),
+		fieldMap:      o.m,
+		where:         o.f.rs,
+		ttl:           mopt.TTL,
+		keys:          o.f.t.info.keys,
+		caseSensitive: mopt.CaseSensitive,
 	}
 }
 
 func (o *singleOp) generateDelete(opt Options) DeleteStatement {
+	mopt := o.f.t.options.Merge(opt)
 	return DeleteStatement{
-		keyspace: o.f.t.keySpace.name,
-		table:    o.f.t.Name(),
-		where:    o.f.rs,
-		keys:     o.f.t.info.keys,
+		keyspace:      o.f.t.keySpace.name,
+		table:         o.f.t.cqlName(mopt.CaseSensitive),
+		columns:       o.columns,
+		where:         o.f.rs,
+		keys:          o.f.t.info.keys,
+		caseSensitive: mopt.CaseSensitive,
 	}
 }
 
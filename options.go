@@ -36,6 +36,38 @@ func (c ClusteringOrderColumn) Field() string {
 	return c.Column
 }
 
+// BucketQuery is the way the time series recipes read the buckets of a time range
+type BucketQuery int
+
+const (
+	// AutoBucketQuery reads time ranges of up to 16 buckets with a single query, like
+	// InBucketQuery, and wider ranges bucket by bucket, like SequentialBucketQuery. This is
+	// the default.
+	AutoBucketQuery BucketQuery = iota
+	// InBucketQuery reads all the buckets of a time range with a single query, using an IN
+	// relation on the bucket. Wide ranges make the coordinator query many partitions at once.
+	InBucketQuery
+	// SequentialBucketQuery reads the buckets of a time range one by one, in clustering order,
+	// and stops once it has read as many rows as the Limit option.
+	SequentialBucketQuery
+	// ParallelBucketQuery reads the buckets of a time range like SequentialBucketQuery, but
+	// reads up to BucketParallelism buckets at a time.
+	ParallelBucketQuery
+)
+
+// MultiReadQuery is the way MapTable.MultiRead and MultimapTable.MultiRead read their keys
+type MultiReadQuery int
+
+const (
+	// InMultiReadQuery reads all the keys with a single query, using an IN relation on the
+	// key. Many keys make the coordinator query many partitions at once. This is the default.
+	InMultiReadQuery MultiReadQuery = iota
+	// ParallelMultiReadQuery splits the keys into chunks of MultiReadChunkSize keys, which are
+	// read by separate queries running MultiReadParallelism at a time. The rows are returned
+	// in the order of the keys, and MultiReadMissingKeys reports the keys which have no rows.
+	ParallelMultiReadQuery
+)
+
 // Options can contain table or statement specific options.
 // The reason for this is because statement specific (TTL, Limit) options make sense as table level options
 // (eg. have default TTL for every Update without specifying it all the time)
@@ -61,19 +93,62 @@ type Options struct {
 	Compressor string
 	// Context allows a request context to passed, which is propagated to the QueryExecutor
 	Context context.Context
+	// CaseSensitive preserves the case of the keyspace, table and column names by quoting them
+	// in statements, rather than lowercasing them. Only enable it for tables which were created
+	// with it, or outside of gocassa with quoted names.
+	CaseSensitive bool
+	// DefaultUnset leaves the columns missing from a row written with Table.SetJSON unchanged,
+	// rather than setting them to null
+	DefaultUnset bool
+	// BucketQuery selects how the time series recipes read the buckets of a time range,
+	// see AutoBucketQuery for the default
+	BucketQuery BucketQuery
+	// BucketParallelism is the number of buckets read at a time with ParallelBucketQuery,
+	// which defaults to 4
+	BucketParallelism int
+	// Bucketing overrides the bucket size a time series table is created with, for example
+	// with calendar months. It changes which partitions the rows are stored in, so it must be
+	// set on the table whenever it is used, and never be changed for a table holding rows. It
+	// isn't part of the table name, consider setting TableName as well.
+	Bucketing Bucketing
+	// IDCodec decodes the ids of the flake series recipes, which are bigflake ids by default.
+	// Like Bucketing, it must be set on the table whenever it is used.
+	IDCodec IDCodec
+	// MultiReadQuery selects how MapTable.MultiRead and MultimapTable.MultiRead read their
+	// keys, see InMultiReadQuery for the default
+	MultiReadQuery MultiReadQuery
+	// MultiReadChunkSize is the number of keys read by each query of ParallelMultiReadQuery,
+	// which defaults to 1, reading every key with its own query
+	MultiReadChunkSize int
+	// MultiReadParallelism is the number of queries of ParallelMultiReadQuery running at a
+	// time, which defaults to 4
+	MultiReadParallelism int
+	// MultiReadMissingKeys makes ParallelMultiReadQuery return a KeysNotFoundError listing the
+	// keys which have no rows, along with the rows of the other keys, rather than skipping them
+	MultiReadMissingKeys bool
 }
 
 // Merge returns a new Options which is a right biased merge of the two initial Options.
 func (o Options) Merge(neu Options) Options {
 	ret := Options{
-		TTL:             o.TTL,
-		Limit:           o.Limit,
-		TableName:       o.TableName,
-		ClusteringOrder: o.ClusteringOrder,
-		Select:          o.Select,
-		CompactStorage:  o.CompactStorage,
-		Compressor:      o.Compressor,
-		Context:         o.Context,
+		TTL:                  o.TTL,
+		Limit:                o.Limit,
+		TableName:            o.TableName,
+		ClusteringOrder:      o.ClusteringOrder,
+		Select:               o.Select,
+		CompactStorage:       o.CompactStorage,
+		Compressor:           o.Compressor,
+		Context:              o.Context,
+		CaseSensitive:        o.CaseSensitive,
+		DefaultUnset:         o.DefaultUnset,
+		BucketQuery:          o.BucketQuery,
+		BucketParallelism:    o.BucketParallelism,
+		Bucketing:            o.Bucketing,
+		IDCodec:              o.IDCodec,
+		MultiReadQuery:       o.MultiReadQuery,
+		MultiReadChunkSize:   o.MultiReadChunkSize,
+		MultiReadParallelism: o.MultiReadParallelism,
+		MultiReadMissingKeys: o.MultiReadMissingKeys,
 	}
 	if neu.TTL != time.Duration(0) {
 		ret.TTL = neu.TTL
@@ -102,6 +177,36 @@ func (o Options) Merge(neu Options) Options {
 	if len(neu.Compressor) > 0 {
 		ret.Compressor = neu.Compressor
 	}
+	if neu.CaseSensitive {
+		ret.CaseSensitive = neu.CaseSensitive
+	}
+	if neu.DefaultUnset {
+		ret.DefaultUnset = neu.DefaultUnset
+	}
+	if neu.BucketQuery != AutoBucketQuery {
+		ret.BucketQuery = neu.BucketQuery
+	}
+	if neu.BucketParallelism != 0 {
+		ret.BucketParallelism = neu.BucketParallelism
+	}
+	if neu.Bucketing != nil {
+		ret.Bucketing = neu.Bucketing
+	}
+	if neu.IDCodec != nil {
+		ret.IDCodec = neu.IDCodec
+	}
+	if neu.MultiReadQuery != InMultiReadQuery {
+		ret.MultiReadQuery = neu.MultiReadQuery
+	}
+	if neu.MultiReadChunkSize != 0 {
+		ret.MultiReadChunkSize = neu.MultiReadChunkSize
+	}
+	if neu.MultiReadParallelism != 0 {
+		ret.MultiReadParallelism = neu.MultiReadParallelism
+	}
+	if neu.MultiReadMissingKeys {
+		ret.MultiReadMissingKeys = neu.MultiReadMissingKeys
+	}
 	// Take the latest context added, so it can be overridden
 	if neu.Context != nil {
 		ret.Context = neu.Context
@@ -16,6 +16,7 @@ type Field struct {
 	index     []int
 	typ       reflect.Type
 	omitEmpty bool
+	options   tagOptions
 }
 
 func (f Field) Name() string {
@@ -30,6 +31,18 @@ func (f Field) Index() []int {
 	return f.index
 }
 
+// HasOption returns whether the field's tag contains the given option flag,
+// for example "set" in `cql:"tags,set"`
+func (f Field) HasOption(name string) bool {
+	return f.options.Contains(name)
+}
+
+// Option returns the value of a "name=value" option in the field's tag, for
+// example "date" in `cql:"day,type=date"`
+func (f Field) Option(name string) (string, bool) {
+	return f.options.Value(name)
+}
+
 func fillField(f Field) Field {
 	f.nameBytes = []byte(f.name)
 
@@ -141,6 +154,7 @@ func typeFields(t reflect.Type) []Field {
 						index:     index,
 						typ:       ft,
 						omitEmpty: opts.Contains("omitempty"),
+						options:   opts,
 					}))
 					if count[f.typ] > 1 {
 						// If there were multiple instances, add a second,
@@ -56,6 +56,15 @@ func StructFieldMap(structType r.Type, lowercaseFields bool) (map[string]Field,
 	return cachedTypeFieldMap(structType, lowercaseFields), nil
 }
 
+// StructFields takes a struct type and returns its fields in declaration
+// order. Field names are determined in the same way as for StructFieldMap.
+func StructFields(structType r.Type) ([]Field, error) {
+	if structType.Kind() != r.Struct {
+		return nil, fmt.Errorf("expected val to be a struct, got %v", structType)
+	}
+	return cachedTypeFields(structType), nil
+}
+
 // MapToStruct converts a map to a struct. It is the inverse of the StructToMap
 // function. For details see StructToMap.
 func MapToStruct(m map[string]interface{}, struc interface{}) error {
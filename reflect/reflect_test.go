@@ -267,6 +267,30 @@ func TestFieldsAndValues(t *testing.T) {
 	}
 }
 
+func TestTagOptions(t *testing.T) {
+	opts := tagOptions("omitempty,type=map<text, frozen<set<int>>>,static")
+	if !opts.Contains("omitempty") || !opts.Contains("static") || opts.Contains("int>>>") {
+		t.Errorf("unexpected options %v", opts.split())
+	}
+	if v, ok := opts.Value("type"); !ok || v != "map<text, frozen<set<int>>>" {
+		t.Errorf("expected type option but got %q", v)
+	}
+	if _, ok := opts.Value("omitempty"); ok {
+		t.Errorf("expected flag not to have a value")
+	}
+
+	type row struct {
+		Day string `cql:"day,type=date"`
+	}
+	fields, err := StructFields(reflect.TypeOf(row{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := fields[0].Option("type"); !ok || v != "date" {
+		t.Errorf("expected type option date but got %q", v)
+	}
+}
+
 func assertFieldsEqual(t *testing.T, a, b []string) {
 	if len(a) != len(b) {
 		t.Errorf("expected fields %v but got %v", a, b)
@@ -48,20 +48,46 @@ func isValidTag(s string) bool {
 // contains a particular substr flag. substr must be surrounded by a
 // string boundary or commas.
 func (o tagOptions) Contains(optionName string) bool {
-	if len(o) == 0 {
-		return false
-	}
-	s := string(o)
-	for s != "" {
-		var next string
-		i := strings.Index(s, ",")
-		if i >= 0 {
-			s, next = s[:i], s[i+1:]
-		}
+	for _, s := range o.split() {
 		if s == optionName {
 			return true
 		}
-		s = next
 	}
 	return false
 }
+
+// Value returns the value of a "name=value" option and whether the option
+// was present at all.
+func (o tagOptions) Value(optionName string) (string, bool) {
+	for _, s := range o.split() {
+		if strings.HasPrefix(s, optionName+"=") {
+			return s[len(optionName)+1:], true
+		}
+	}
+	return "", false
+}
+
+// split returns the individual options. Commas nested within angle brackets
+// are not treated as separators so that options such as
+// "type=map<text, int>" are kept intact.
+func (o tagOptions) split() []string {
+	var result []string
+	s, depth, start := string(o), 0, 0
+	for i, c := range s {
+		switch c {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(s) {
+		result = append(result, s[start:])
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package gocassa
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 	"time"
 )
@@ -18,6 +19,9 @@ const (
 	CmpGreaterThanOrEquals                   // larger than or equal (foo >= 1)
 	CmpLesserThan                            // less than (foo < 1)
 	CmpLesserThanOrEquals                    // less than or equal (foo <= 1)
+	CmpContains                              // collection membership (foo CONTAINS bar)
+	CmpContainsKey                           // map key membership (foo CONTAINS KEY bar)
+	CmpNotEquals                             // inequality (foo != bar)
 )
 
 // Relation describes the comparison of a field against a list of terms
@@ -25,17 +29,40 @@ const (
 type Relation struct {
 	cmp   Comparator
 	field string
+	// fields is set instead of field for multi-column (tuple) relations,
+	// such as (foo, bar) > (1, 2)
+	fields []string
 	// terms represents the list of terms on the right hand side to match
 	// against. It is expected that all comparators except the CmpIn have
-	// exactly one term.
+	// exactly one term, except for tuple relations which have one term
+	// per field.
 	terms []interface{}
 }
 
-// Field provides the field name for this relation
+// Field provides the field name for this relation. For tuple relations it
+// is the first of the fields
 func (r Relation) Field() string {
+	if r.IsTuple() {
+		return r.fields[0]
+	}
 	return r.field
 }
 
+// Fields provides the field names of this relation, which has more than one
+// field if it is a tuple relation
+func (r Relation) Fields() []string {
+	if r.IsTuple() {
+		return r.fields
+	}
+	return []string{r.field}
+}
+
+// IsTuple returns whether this is a multi-column relation, comparing the
+// tuple of its fields to the tuple of its terms
+func (r Relation) IsTuple() bool {
+	return len(r.fields) > 0
+}
+
 // Comparator provides the comparator for this relation
 func (r Relation) Comparator() Comparator {
 	return r.cmp
@@ -50,7 +77,7 @@ func (r Relation) Terms() []interface{} {
 func anyEquals(value interface{}, terms []interface{}) bool {
 	primVal := convertToPrimitive(value)
 	for _, term := range terms {
-		if primVal == convertToPrimitive(term) {
+		if builtinEquals(primVal, convertToPrimitive(term)) {
 			return true
 		}
 	}
@@ -73,6 +100,10 @@ func convertToPrimitive(i interface{}) interface{} {
 		// We mostly want this to allow comparisons of blob types in the primary key of a table,
 		// since []byte are not `==` comparable in go, but strings are
 		return string(v)
+	case net.IP:
+		// IP addresses are compared byte-wise in their 16 byte form so that
+		// IPv4 addresses match regardless of how they were parsed
+		return string(v.To16())
 	default:
 		// If the underlying type is a string, we want to represent this value
 		// as a string for comparison across proxy types.
@@ -83,12 +114,25 @@ func convertToPrimitive(i interface{}) interface{} {
 	}
 }
 
+// accept returns whether the value satisfies the relation. The value of a
+// tuple relation is the slice of the values of its fields
 func (r Relation) accept(i interface{}) bool {
 	var result bool
 	var err error
 
-	if r.Comparator() == CmpEquality || r.Comparator() == CmpIn {
+	switch r.Comparator() {
+	case CmpEquality, CmpIn:
 		return anyEquals(i, r.Terms())
+	case CmpNotEquals:
+		return !anyEquals(i, r.Terms())
+	case CmpContains:
+		return collectionContains(i, r.Terms()[0], false)
+	case CmpContainsKey:
+		return collectionContains(i, r.Terms()[0], true)
+	}
+
+	if r.IsTuple() {
+		return r.acceptTuple(i)
 	}
 
 	a, b := convertToPrimitive(i), convertToPrimitive(r.Terms()[0])
@@ -98,10 +142,10 @@ func (r Relation) accept(i interface{}) bool {
 		result, err = builtinGreaterThan(a, b)
 	case CmpGreaterThanOrEquals:
 		result, err = builtinGreaterThan(a, b)
-		result = result || a == b
+		result = result || builtinEquals(a, b)
 	case CmpLesserThanOrEquals:
 		result, err = builtinLessThan(a, b)
-		result = result || a == b
+		result = result || builtinEquals(a, b)
 	case CmpLesserThan:
 		result, err = builtinLessThan(a, b)
 	}
@@ -109,6 +153,64 @@ func (r Relation) accept(i interface{}) bool {
 	return err == nil && result
 }
 
+// acceptTuple compares the values to the terms lexicographically, the way
+// Cassandra compares clustering columns
+func (r Relation) acceptTuple(i interface{}) bool {
+	values, ok := i.([]interface{})
+	if !ok || len(values) != len(r.terms) {
+		return false
+	}
+
+	for idx, value := range values {
+		a, b := convertToPrimitive(value), convertToPrimitive(r.terms[idx])
+		if builtinEquals(a, b) {
+			continue
+		}
+
+		var result bool
+		var err error
+		switch r.Comparator() {
+		case CmpGreaterThan, CmpGreaterThanOrEquals:
+			result, err = builtinGreaterThan(a, b)
+		case CmpLesserThan, CmpLesserThanOrEquals:
+			result, err = builtinLessThan(a, b)
+		}
+		return err == nil && result
+	}
+
+	// All the values are equal to the terms
+	return r.Comparator() == CmpGreaterThanOrEquals || r.Comparator() == CmpLesserThanOrEquals
+}
+
+// collectionContains returns whether the list, set or map contains the term,
+// or has it as a key if keys is set
+func collectionContains(collection, term interface{}, keys bool) bool {
+	v := reflect.ValueOf(collection)
+	terms := toI(term)
+	switch v.Kind() {
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			elem := k
+			if !keys {
+				elem = v.MapIndex(k)
+			}
+			if anyEquals(elem.Interface(), terms) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if keys {
+			return false
+		}
+		for idx := 0; idx < v.Len(); idx++ {
+			if anyEquals(v.Index(idx).Interface(), terms) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func toI(i interface{}) []interface{} {
 	return []interface{}{i}
 }
@@ -160,3 +262,70 @@ func LTE(field string, term interface{}) Relation {
 		terms: toI(term),
 	}
 }
+
+// NotEq matches the rows where the field isn't equal to the term. Cassandra
+// only supports it in some queries, such as conditional updates or when
+// filtering
+func NotEq(field string, term interface{}) Relation {
+	return Relation{
+		cmp:   CmpNotEquals,
+		field: field,
+		terms: toI(term),
+	}
+}
+
+// Contains matches the rows where the list, set or map in the field contains
+// the term. It requires an index on the field or filtering to be allowed
+func Contains(field string, term interface{}) Relation {
+	return Relation{
+		cmp:   CmpContains,
+		field: field,
+		terms: toI(term),
+	}
+}
+
+// ContainsKey matches the rows where the map in the field has the term as a
+// key. It requires an index on the keys of the field or filtering to be
+// allowed
+func ContainsKey(field string, term interface{}) Relation {
+	return Relation{
+		cmp:   CmpContainsKey,
+		field: field,
+		terms: toI(term),
+	}
+}
+
+func tuple(cmp Comparator, fields []string, terms []interface{}) Relation {
+	if len(fields) == 0 || len(fields) != len(terms) {
+		panic(fmt.Sprintf("Tuple relation on %v fields has %v terms", len(fields), len(terms)))
+	}
+	return Relation{
+		cmp:    cmp,
+		fields: fields,
+		terms:  terms,
+	}
+}
+
+// TupleGT matches the rows where the tuple of the (clustering) fields is
+// greater than the tuple of the terms, for example (a, b) > (1, 2). This
+// slices the rows of a partition in clustering order, for example to
+// paginate over a composite clustering key
+func TupleGT(fields []string, terms ...interface{}) Relation {
+	return tuple(CmpGreaterThan, fields, terms)
+}
+
+// TupleGTE is like TupleGT, but also matches the row equal to the terms
+func TupleGTE(fields []string, terms ...interface{}) Relation {
+	return tuple(CmpGreaterThanOrEquals, fields, terms)
+}
+
+// TupleLT matches the rows where the tuple of the (clustering) fields is
+// less than the tuple of the terms, for example (a, b) < (1, 2)
+func TupleLT(fields []string, terms ...interface{}) Relation {
+	return tuple(CmpLesserThan, fields, terms)
+}
+
+// TupleLTE is like TupleLT, but also matches the row equal to the terms
+func TupleLTE(fields []string, terms ...interface{}) Relation {
+	return tuple(CmpLesserThanOrEquals, fields, terms)
+}
@@ -1,8 +1,12 @@
 package gocassa
 
 import (
+	"math/big"
+	"net"
 	"testing"
 	"time"
+
+	"gopkg.in/inf.v0"
 )
 
 func TestAnyEquals(t *testing.T) {
@@ -22,6 +26,9 @@ func TestAnyEquals(t *testing.T) {
 		{1950, makeInterfaceArray(1950)},
 		{[]byte{0x00, 0xFF, 0x01, 0x99, 0xEA}, makeInterfaceArray("\x00\xFF\x01\x99\xEA")},
 		{name("Bingo 🐕"), makeInterfaceArray("Bingo 🐕")},
+		{big.NewInt(42), makeInterfaceArray(big.NewInt(1), big.NewInt(42))},
+		{inf.NewDec(150, 2), makeInterfaceArray(inf.NewDec(15, 1))},
+		{net.ParseIP("10.0.0.1"), makeInterfaceArray(net.IPv4(10, 0, 0, 1).To4())},
 	}
 
 	for _, tc := range testCases {
@@ -32,6 +39,50 @@ func TestAnyEquals(t *testing.T) {
 	}
 }
 
+func TestAcceptScalarTypes(t *testing.T) {
+	if !GT("n", big.NewInt(10)).accept(big.NewInt(11)) || GT("n", big.NewInt(10)).accept(big.NewInt(10)) {
+		t.Fatal("unexpected varint comparison")
+	}
+	if !LTE("n", inf.NewDec(15, 1)).accept(inf.NewDec(150, 2)) || LT("n", inf.NewDec(15, 1)).accept(inf.NewDec(16, 1)) {
+		t.Fatal("unexpected decimal comparison")
+	}
+	if !LT("ip", net.ParseIP("10.0.0.2")).accept(net.ParseIP("10.0.0.1")) {
+		t.Fatal("unexpected inet comparison")
+	}
+	if !GTE("n", int16(-3)).accept(int16(-3)) || !LT("n", int8(5)).accept(int8(-5)) {
+		t.Fatal("unexpected small integer comparison")
+	}
+}
+
+func TestAcceptCollectionsAndTuples(t *testing.T) {
+	if !Contains("tags", "b").accept([]string{"a", "b"}) || Contains("tags", "c").accept([]string{"a", "b"}) {
+		t.Fatal("unexpected list membership")
+	}
+	if !ContainsKey("m", "a").accept(map[string]int{"a": 1}) || ContainsKey("m", 1).accept(map[string]int{"a": 1}) {
+		t.Fatal("unexpected map key membership")
+	}
+	if !Contains("m", 1).accept(map[string]int{"a": 1}) || ContainsKey("tags", "a").accept([]string{"a"}) {
+		t.Fatal("unexpected map value membership")
+	}
+	if !NotEq("n", 1).accept(2) || NotEq("n", 1).accept(1) {
+		t.Fatal("unexpected inequality")
+	}
+
+	fields := []string{"a", "b"}
+	if !TupleGT(fields, 1, "x").accept([]interface{}{1, "y"}) || !TupleGT(fields, 1, "x").accept([]interface{}{2, "a"}) {
+		t.Fatal("expected tuple to be greater")
+	}
+	if TupleGT(fields, 1, "x").accept([]interface{}{1, "x"}) || TupleGT(fields, 1, "x").accept([]interface{}{0, "z"}) {
+		t.Fatal("expected tuple not to be greater")
+	}
+	if !TupleGTE(fields, 1, "x").accept([]interface{}{1, "x"}) || !TupleLTE(fields, 1, "x").accept([]interface{}{1, "x"}) {
+		t.Fatal("expected equal tuples to match inclusive bounds")
+	}
+	if !TupleLT(fields, 1, "x").accept([]interface{}{0, "z"}) || TupleLT(fields, 1, "x").accept([]interface{}{1, "x"}) {
+		t.Fatal("unexpected tuple less than comparison")
+	}
+}
+
 func makeInterfaceArray(terms ...interface{}) []interface{} {
 	interfaceSlice := make([]interface{}, len(terms))
 	for i, d := range terms {
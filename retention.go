@@ -0,0 +1,61 @@
+package gocassa
+
+import (
+	"context"
+	"time"
+)
+
+// BucketRetention deletes the old buckets of the time series and flake series
+// recipes. Every bucket is deleted with a single partition deletion, which is
+// much cheaper than deleting its rows one by one or expiring them with a TTL.
+type BucketRetention struct {
+	// Rate is the maximum number of buckets deleted per second, with no limit if it is 0
+	Rate float64
+	// DryRun lists the buckets which would be deleted without deleting them
+	DryRun bool
+}
+
+// DeleteBefore deletes the buckets starting with first which end before or at cutoff, and
+// returns the start of each of them, in order. Buckets are enumerated from first, which
+// should be the bucket of the oldest row kept (or the cutoff of the previous run), as
+// deleting a partition holding no rows still writes a tombstone. To delete the buckets
+// of a single value of the multi recipes, pass the Buckets of the value.
+//
+// On error the buckets deleted so far are returned along with the error.
+func (r BucketRetention) DeleteBefore(ctx context.Context, first Buckets, cutoff time.Time) ([]time.Time, error) {
+	var interval time.Duration
+	if r.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / r.Rate)
+	}
+
+	deleted := []time.Time{}
+	for b := first; !b.Next().Bucket().After(cutoff); b = b.Next() {
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+		if len(deleted) > 0 && interval > 0 && !r.DryRun {
+			if err := sleepContext(ctx, interval); err != nil {
+				return deleted, err
+			}
+		}
+		if !r.DryRun {
+			if err := b.Filter().Delete().RunWithContext(ctx); err != nil {
+				return deleted, err
+			}
+		}
+		deleted = append(deleted, b.Bucket())
+	}
+	return deleted, nil
+}
+
+// sleepContext waits for the given duration, or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,6 +1,7 @@
 package gocassa
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -293,3 +294,67 @@ type IgnoreFieldType struct{}
 func (i *IgnoreFieldType) UnmarshalCQL(_ gocql.TypeInfo, _ []byte) error {
 	return nil
 }
+
+// valueScanner implements the Scanner interface for queries returning a
+// single value, such as aggregates. It scans the first column of the first
+// row into the result, which must be a pointer
+type valueScanner struct {
+	result interface{}
+}
+
+func newValueScanner(result interface{}) Scanner {
+	return &valueScanner{result: result}
+}
+
+func (s *valueScanner) ScanIter(iter Scannable) (int, error) {
+	if reflect.TypeOf(s.result) == nil || reflect.TypeOf(s.result).Kind() != reflect.Ptr {
+		return 0, fmt.Errorf("can only decode a single value into a pointer, not %T", s.result)
+	}
+	if !iter.Next() {
+		if err := iter.Err(); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	if err := iter.Scan(s.result); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (s *valueScanner) Result() interface{} {
+	return s.result
+}
+
+// jsonScanner implements the Scanner interface for SELECT JSON queries, which
+// return a single JSON text column per row. It appends the rows to the result,
+// which must be a *[]json.RawMessage
+type jsonScanner struct {
+	result interface{}
+}
+
+func newJSONScanner(result interface{}) Scanner {
+	return &jsonScanner{result: result}
+}
+
+func (s *jsonScanner) ScanIter(iter Scannable) (int, error) {
+	out, ok := s.result.(*[]json.RawMessage)
+	if !ok || out == nil {
+		return 0, fmt.Errorf("can only decode JSON rows into a *[]json.RawMessage, not %T", s.result)
+	}
+
+	rows := []json.RawMessage{}
+	for iter.Next() {
+		var row string
+		if err := iter.Scan(&row); err != nil {
+			return len(rows), err
+		}
+		rows = append(rows, json.RawMessage(row))
+	}
+	*out = rows
+	return len(rows), nil
+}
+
+func (s *jsonScanner) Result() interface{} {
+	return s.result
+}
@@ -1,6 +1,7 @@
 package gocassa
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -229,6 +230,35 @@ func TestScanIterComposite(t *testing.T) {
 	iter.Reset()
 }
 
+func TestScanIterValue(t *testing.T) {
+	iter := newMockIterator([]map[string]interface{}{{"count": int64(42)}}, []string{"count"})
+	var count int64
+	rowsRead, err := newValueScanner(&count).ScanIter(iter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rowsRead)
+	assert.Equal(t, int64(42), count)
+
+	noResultsIter := newMockIterator([]map[string]interface{}{}, []string{"count"})
+	rowsRead, err = newValueScanner(&count).ScanIter(noResultsIter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rowsRead)
+
+	_, err = newValueScanner(count).ScanIter(iter)
+	assert.Error(t, err)
+}
+
+func TestScanIterJSON(t *testing.T) {
+	iter := newMockIterator([]map[string]interface{}{
+		{"[json]": `{"id": "1"}`},
+		{"[json]": `{"id": "2"}`},
+	}, []string{"[json]"})
+	var rows []json.RawMessage
+	rowsRead, err := newJSONScanner(&rows).ScanIter(iter)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rowsRead)
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id": "1"}`), json.RawMessage(`{"id": "2"}`)}, rows)
+}
+
 func TestScanIterEmbedded(t *testing.T) {
 	results := []map[string]interface{}{
 		{"id": "acc_abcd1", "name": "John", "created": "2018-05-01 19:00:00+0000"},
@@ -0,0 +1,160 @@
+package gocassa
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+const shardFieldName = "shard"
+
+// shardedTimeSeriesT is a time series whose buckets are split into shards,
+// the partition key being the bucket and the shard of the id of the row
+type shardedTimeSeriesT struct {
+	t          Table
+	timeField  string
+	idField    string
+	idType     gocql.Type // CQL type the ids are marshalled as to be hashed
+	shards     int
+	bucketSize time.Duration
+}
+
+func (o *shardedTimeSeriesT) Table() Table                        { return o.t }
+func (o *shardedTimeSeriesT) Create() error                       { return o.Table().Create() }
+func (o *shardedTimeSeriesT) CreateIfNotExist() error             { return o.Table().CreateIfNotExist() }
+func (o *shardedTimeSeriesT) Name() string                        { return o.Table().Name() }
+func (o *shardedTimeSeriesT) Recreate() error                     { return o.Table().Recreate() }
+func (o *shardedTimeSeriesT) CreateStatement() (Statement, error) { return o.Table().CreateStatement() }
+func (o *shardedTimeSeriesT) CreateIfNotExistStatement() (Statement, error) {
+	return o.Table().CreateIfNotExistStatement()
+}
+func (o *shardedTimeSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
+
+// shard returns the shard of the rows with the given id, from a hash of the
+// id marshalled as a value of the id column, so that it doesn't depend on the
+// Go type the id is given as
+func (o *shardedTimeSeriesT) shard(id interface{}) (int, error) {
+	marshalled, err := gocql.Marshal(gocqlTypeInfo{proto: 0x03, typ: o.idType}, id)
+	if err != nil {
+		return 0, fmt.Errorf("Can't shard id %v: %v", id, err)
+	}
+	h := fnv.New32a()
+	h.Write(marshalled)
+	return int(h.Sum32() % uint32(o.shards)), nil
+}
+
+func (o *shardedTimeSeriesT) Set(v interface{}) Op {
+	m, ok := toMap(v)
+	if !ok {
+		panic("Can't set: not able to convert")
+	}
+	if tim, ok := m[o.timeField].(time.Time); !ok {
+		panic("timeField is not actually a time.Time")
+	} else {
+		m[bucketFieldName] = o.bucketing().Bucket(tim)
+	}
+	shard, err := o.shard(m[o.idField])
+	if err != nil {
+		return errOp{err: err}
+	}
+	m[shardFieldName] = shard
+	return o.Table().Set(m)
+}
+
+func (o *shardedTimeSeriesT) Update(timeStamp time.Time, id interface{}, m map[string]interface{}) Op {
+	shard, err := o.shard(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	bucket := o.bucketing().Bucket(timeStamp)
+	return o.Table().
+		Where(Eq(bucketFieldName, bucket),
+			Eq(shardFieldName, shard),
+			Eq(o.timeField, timeStamp),
+			Eq(o.idField, id)).
+		Update(m)
+}
+
+func (o *shardedTimeSeriesT) UpdateStatic(timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(timeStamp).Filter().Update(m)
+}
+
+func (o *shardedTimeSeriesT) Delete(timeStamp time.Time, id interface{}) Op {
+	shard, err := o.shard(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	bucket := o.bucketing().Bucket(timeStamp)
+	return o.Table().
+		Where(Eq(bucketFieldName, bucket),
+			Eq(shardFieldName, shard),
+			Eq(o.timeField, timeStamp),
+			Eq(o.idField, id)).
+		Delete()
+}
+
+func (o *shardedTimeSeriesT) Read(timeStamp time.Time, id, pointer interface{}) Op {
+	shard, err := o.shard(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	bucket := o.bucketing().Bucket(timeStamp)
+	return o.Table().
+		Where(Eq(bucketFieldName, bucket),
+			Eq(shardFieldName, shard),
+			Eq(o.timeField, timeStamp),
+			Eq(o.idField, id)).
+		ReadOne(pointer)
+}
+
+func (o *shardedTimeSeriesT) List(startTime time.Time, endTime time.Time, pointerToASlice interface{}) Op {
+	return newBucketsOp(o.Buckets(startTime), o.Buckets(endTime), endTime,
+		[]Relation{GTE(o.timeField, startTime), LTE(o.timeField, endTime)},
+		pointerToASlice).
+		withShards(shardFieldName, o.shards, o.timeField)
+}
+
+func (o *shardedTimeSeriesT) ListLatest(start, before time.Time, limit int, pointerToASlice interface{}) Op {
+	return newLatestOp(o.Buckets(start), o.Buckets(before), o.timeField, start, before, limit, pointerToASlice).
+		withShards(shardFieldName, o.shards, o.timeField)
+}
+
+func (o *shardedTimeSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
+}
+
+// Buckets returns the buckets starting with the one of the given time. The
+// filter of a bucket selects all of its shards.
+func (o *shardedTimeSeriesT) Buckets(start time.Time) Buckets {
+	shards := make([]interface{}, o.shards)
+	for i := range shards {
+		shards[i] = i
+	}
+	return bucketIter{
+		v:         start,
+		bucketing: o.bucketing(),
+		field:     bucketFieldName,
+		invariant: o.Table().Where(In(shardFieldName, shards...))}
+}
+
+func (o *shardedTimeSeriesT) Retention(ctx context.Context, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(start), cutoff)
+}
+
+func (o *shardedTimeSeriesT) WithOptions(opt Options) TimeSeriesTable {
+	result := &shardedTimeSeriesT{
+		t:          o.Table().WithOptions(opt),
+		timeField:  o.timeField,
+		idField:    o.idField,
+		idType:     o.idType,
+		shards:     o.shards,
+		bucketSize: o.bucketSize,
+	}
+	reregister(o, result)
+	return result
+}
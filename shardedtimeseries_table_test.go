@@ -0,0 +1,56 @@
+package gocassa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedTimeSeriesTableStatements(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").ShardedTimeSeriesTable("trip", "Time", "Id", 8, time.Hour, Trip{})
+	assert.Equal(t, "trip_shardedTimeSeries_Time_Id_8_1h0m0s", tbl.Name())
+
+	stmt, err := tbl.CreateStatement()
+	assert.NoError(t, err)
+	assert.Contains(t, stmt.Query(), "PRIMARY KEY ((bucket, shard), time, id)")
+
+	// Every shard of a bucket is read separately
+	trips := []Trip{}
+	op := tbl.List(parse("2006 Jan 2 10:00:00"), parse("2006 Jan 2 15:04:05"), &trips)
+	assert.Equal(t, "SELECT id, time, bucket, shard FROM ks1.trip_shardedTimeSeries_Time_Id_8_1h0m0s WHERE bucket = ? AND shard = ? AND time >= ? AND time <= ?", op.GenerateStatement().Query())
+
+	stmt = tbl.Read(parse("2006 Jan 2 10:00:00"), "1", &Trip{}).GenerateStatement()
+	assert.Equal(t, "SELECT id, time, bucket, shard FROM ks1.trip_shardedTimeSeries_Time_Id_8_1h0m0s WHERE bucket = ? AND shard = ? AND time = ? AND id = ?", stmt.Query())
+	shard, err := tbl.(*shardedTimeSeriesT).shard("1")
+	assert.NoError(t, err)
+	assert.Equal(t, shard, stmt.Values()[1])
+}
+
+type shardedCount struct {
+	Id    int64
+	Time  time.Time
+	Count int
+}
+
+func TestShardedTimeSeriesTableShard(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").ShardedTimeSeriesTable("count", "Time", "Id", 8, time.Hour, shardedCount{})
+	sharded := tbl.(*shardedTimeSeriesT)
+
+	// The shard of an id doesn't depend on the Go type it is given as
+	for id := int64(0); id < 100; id++ {
+		shard, err := sharded.shard(id)
+		assert.NoError(t, err)
+		assert.True(t, shard >= 0 && shard < 8)
+		for _, other := range []interface{}{int(id), int32(id), uint8(id)} {
+			otherShard, err := sharded.shard(other)
+			assert.NoError(t, err)
+			assert.Equal(t, shard, otherShard)
+		}
+	}
+
+	// Ids which can't be marshalled as the id column fail the op
+	_, err := sharded.shard([]string{"1"})
+	assert.Error(t, err)
+	assert.Error(t, tbl.Read(parse("2006 Jan 2 10:00:00"), []string{"1"}, &shardedCount{}).Run())
+}
@@ -17,6 +17,10 @@ type SelectStatement struct {
 	limit          int                     // limit count, 0 means no limit
 	allowFiltering bool                    // whether we should allow filtering
 	keys           Keys                    // partition / clustering keys for table
+	caseSensitive  bool                    // whether the case of identifiers is preserved
+	aggregate      AggregateFunc           // aggregate function applied to the fields, if any
+	distinct       bool                    // whether only distinct (partition key) rows are selected
+	json           bool                    // whether the rows are selected as JSON
 }
 
 // NewSelectStatement adds the ability to craft a new SelectStatement
@@ -58,19 +62,25 @@ func (s SelectStatement) Values() []interface{} {
 // QueryAndValues returns the CQL query and any bind values
 func (s SelectStatement) QueryAndValues() (string, []interface{}) {
 	values := make([]interface{}, 0)
-	query := []string{
-		"SELECT",
-		strings.Join(s.fields, ", "),
-		fmt.Sprintf("FROM %s.%s", s.Keyspace(), s.Table()),
+	query := []string{"SELECT"}
+	if s.JSON() {
+		query = append(query, "JSON")
 	}
+	if s.Distinct() {
+		query = append(query, "DISTINCT")
+	}
+	query = append(query,
+		generateSelectorsCQL(s.fields, s.Aggregate(), s.caseSensitive),
+		"FROM "+tableIdentifier(s.Keyspace(), s.Table(), s.caseSensitive),
+	)
 
-	whereCQL, whereValues := generateWhereCQL(s.Relations())
+	whereCQL, whereValues := generateWhereCQL(s.Relations(), s.caseSensitive)
 	if whereCQL != "" {
 		query = append(query, "WHERE", whereCQL)
 		values = append(values, whereValues...)
 	}
 
-	orderByCQL := generateOrderByCQL(s.OrderBy())
+	orderByCQL := generateOrderByCQL(s.OrderBy(), s.caseSensitive)
 	if orderByCQL != "" {
 		query = append(query, "ORDER BY", orderByCQL)
 	}
@@ -156,19 +166,72 @@ func (s SelectStatement) WithAllowFiltering(enabled bool) SelectStatement {
 	return s
 }
 
+// Aggregate returns the aggregate function applied to the selected fields,
+// or an empty AggregateFunc if the rows are selected as they are
+func (s SelectStatement) Aggregate() AggregateFunc {
+	return s.aggregate
+}
+
+// WithAggregate applies the aggregate function to the selected fields. The
+// field "*" can be used to count the rows
+func (s SelectStatement) WithAggregate(fn AggregateFunc) SelectStatement {
+	s.aggregate = fn
+	return s
+}
+
+// JSON returns whether the rows are selected as JSON (SELECT JSON), in which
+// case every row has a single text column with the JSON of the selected fields
+func (s SelectStatement) JSON() bool {
+	return s.json
+}
+
+// WithJSON allows toggling of the selection of rows as JSON
+func (s SelectStatement) WithJSON(enabled bool) SelectStatement {
+	s.json = enabled
+	return s
+}
+
+// Distinct returns whether only distinct rows are selected (SELECT DISTINCT)
+func (s SelectStatement) Distinct() bool {
+	return s.distinct
+}
+
+// WithDistinct allows toggling of the selection of distinct rows. Cassandra
+// only allows selecting distinct partition keys (and static columns)
+func (s SelectStatement) WithDistinct(enabled bool) SelectStatement {
+	s.distinct = enabled
+	return s
+}
+
 // Keys provides the Partition / Clustering keys defined by the table recipe
 func (s SelectStatement) Keys() Keys {
 	return s.keys
 }
 
+// CaseSensitive returns whether the case of the keyspace, table and column
+// names is preserved (by quoting them) rather than lowercased
+func (s SelectStatement) CaseSensitive() bool {
+	return s.caseSensitive
+}
+
+// WithCaseSensitive allows toggling whether the case of the keyspace, table
+// and column names is preserved
+func (s SelectStatement) WithCaseSensitive(enabled bool) SelectStatement {
+	s.caseSensitive = enabled
+	return s
+}
+
 // InsertStatement represents an INSERT query to write some data in C*
 // It satisfies the Statement interface
 type InsertStatement struct {
-	keyspace string                 // name of the keyspace
-	table    string                 // name of the table
-	fieldMap map[string]interface{} // fields to be inserted
-	ttl      time.Duration          // ttl of the row
-	keys     Keys                   // partition / clustering keys for table
+	keyspace      string                 // name of the keyspace
+	table         string                 // name of the table
+	fieldMap      map[string]interface{} // fields to be inserted
+	json          []byte                 // JSON row to be inserted instead of the fieldMap
+	defaultUnset  bool                   // whether fields missing from the JSON row are left unset
+	ttl           time.Duration          // ttl of the row
+	keys          Keys                   // partition / clustering keys for table
+	caseSensitive bool                   // whether the case of identifiers is preserved
 }
 
 // NewInsertStatement adds the ability to craft a new InsertStatement
@@ -194,6 +257,30 @@ func NewInsertStatement(keyspace, table string, fieldMap map[string]interface{},
 	return stmt, nil
 }
 
+// NewInsertJSONStatement adds the ability to craft a new InsertStatement
+// which inserts a row given as a JSON object (INSERT INTO ... JSON ?)
+// This function will error if the parameters passed in are invalid
+func NewInsertJSONStatement(keyspace, table string, row []byte, keys Keys) (InsertStatement, error) {
+	stmt := InsertStatement{}
+	if keyspace == "" || table == "" {
+		return stmt, fmt.Errorf("keyspace and table can't be empty")
+	}
+
+	if len(row) == 0 {
+		return stmt, fmt.Errorf("row must be a JSON object to insert")
+	}
+
+	if len(keys.PartitionKeys) == 0 {
+		return stmt, fmt.Errorf("partition key should be supplied")
+	}
+
+	stmt.keyspace = keyspace
+	stmt.table = table
+	stmt.json = row
+	stmt.keys = keys
+	return stmt, nil
+}
+
 // Query provides the CQL query string for an INSERT INTO query
 func (s InsertStatement) Query() string {
 	query, _ := s.QueryAndValues()
@@ -208,14 +295,17 @@ func (s InsertStatement) Values() []interface{} {
 
 // QueryAndValues returns the CQL query and any bind values
 func (s InsertStatement) QueryAndValues() (string, []interface{}) {
-	query := []string{"INSERT INTO", fmt.Sprintf("%s.%s", s.Keyspace(), s.Table())}
+	query := []string{"INSERT INTO", tableIdentifier(s.Keyspace(), s.Table(), s.caseSensitive)}
+	if s.JSON() != nil {
+		return s.jsonQueryAndValues(query)
+	}
 
 	fieldMap := s.FieldMap()
 	fieldNames := make([]string, 0, len(fieldMap))
 	placeholders := make([]string, 0, len(fieldMap))
 	values := make([]interface{}, 0, len(fieldMap))
 	for _, field := range sortedKeys(fieldMap) {
-		fieldNames = append(fieldNames, strings.ToLower(field))
+		fieldNames = append(fieldNames, columnName(field, s.caseSensitive))
 		placeholders = append(placeholders, "?")
 		values = append(values, fieldMap[field])
 	}
@@ -232,6 +322,19 @@ func (s InsertStatement) QueryAndValues() (string, []interface{}) {
 	return strings.Join(query, " "), values
 }
 
+func (s InsertStatement) jsonQueryAndValues(query []string) (string, []interface{}) {
+	values := []interface{}{string(s.JSON())}
+	query = append(query, "JSON ?")
+	if s.DefaultUnset() {
+		query = append(query, "DEFAULT UNSET")
+	}
+	if s.TTL() > time.Duration(0) {
+		query = append(query, "USING TTL ?")
+		values = append(values, int(s.TTL().Seconds()))
+	}
+	return strings.Join(query, " "), values
+}
+
 // Keyspace returns the name of the Keyspace for the statement
 func (s InsertStatement) Keyspace() string {
 	return s.keyspace
@@ -248,6 +351,25 @@ func (s InsertStatement) FieldMap() map[string]interface{} {
 	return s.fieldMap
 }
 
+// JSON returns the row to be inserted as a JSON object, or nil if the
+// fields of the FieldMap are inserted
+func (s InsertStatement) JSON() []byte {
+	return s.json
+}
+
+// DefaultUnset returns whether the fields missing from the JSON row are left
+// unchanged (DEFAULT UNSET), rather than set to null
+func (s InsertStatement) DefaultUnset() bool {
+	return s.defaultUnset
+}
+
+// WithDefaultUnset allows toggling whether the fields missing from the JSON
+// row are left unchanged rather than set to null
+func (s InsertStatement) WithDefaultUnset(enabled bool) InsertStatement {
+	s.defaultUnset = enabled
+	return s
+}
+
 // TTL returns the Time-To-Live for this row statement. A duration of 0
 // means there is no TTL
 func (s InsertStatement) TTL() time.Duration {
@@ -272,15 +394,29 @@ func (s InsertStatement) Keys() Keys {
 	return s.keys
 }
 
+// CaseSensitive returns whether the case of the keyspace, table and column
+// names is preserved (by quoting them) rather than lowercased
+func (s InsertStatement) CaseSensitive() bool {
+	return s.caseSensitive
+}
+
+// WithCaseSensitive allows toggling whether the case of the keyspace, table
+// and column names is preserved
+func (s InsertStatement) WithCaseSensitive(enabled bool) InsertStatement {
+	s.caseSensitive = enabled
+	return s
+}
+
 // UpdateStatement represents an UPDATE query to update some data in C*
 // It satisfies the Statement interface
 type UpdateStatement struct {
-	keyspace string                 // name of the keyspace
-	table    string                 // name of the table
-	fieldMap map[string]interface{} // fields to be updated
-	where    []Relation             // where filter clauses
-	ttl      time.Duration          // ttl of the row
-	keys     Keys                   // partition / clustering keys for table
+	keyspace      string                 // name of the keyspace
+	table         string                 // name of the table
+	fieldMap      map[string]interface{} // fields to be updated
+	where         []Relation             // where filter clauses
+	ttl           time.Duration          // ttl of the row
+	keys          Keys                   // partition / clustering keys for table
+	caseSensitive bool                   // whether the case of identifiers is preserved
 }
 
 // NewUpdateStatement adds the ability to craft a new UpdateStatement
@@ -326,7 +462,7 @@ func (s UpdateStatement) Values() []interface{} {
 // QueryAndValues returns the CQL query and any bind values
 func (s UpdateStatement) QueryAndValues() (string, []interface{}) {
 	values := make([]interface{}, 0)
-	query := []string{"UPDATE", fmt.Sprintf("%s.%s", s.Keyspace(), s.Table())}
+	query := []string{"UPDATE", tableIdentifier(s.Keyspace(), s.Table(), s.caseSensitive)}
 
 	// Determine if we need to set a TTL
 	if s.TTL() > 0 {
@@ -334,11 +470,11 @@ func (s UpdateStatement) QueryAndValues() (string, []interface{}) {
 		values = append(values, int(s.TTL().Seconds()))
 	}
 
-	setCQL, setValues := generateUpdateSetCQL(s.FieldMap())
+	setCQL, setValues := generateUpdateSetCQL(s.FieldMap(), s.caseSensitive)
 	query = append(query, "SET", setCQL)
 	values = append(values, setValues...)
 
-	whereCQL, whereValues := generateWhereCQL(s.Relations())
+	whereCQL, whereValues := generateWhereCQL(s.Relations(), s.caseSensitive)
 	if whereCQL != "" {
 		query = append(query, "WHERE", whereCQL)
 		values = append(values, whereValues...)
@@ -392,13 +528,28 @@ func (s UpdateStatement) Keys() Keys {
 	return s.keys
 }
 
+// CaseSensitive returns whether the case of the keyspace, table and column
+// names is preserved (by quoting them) rather than lowercased
+func (s UpdateStatement) CaseSensitive() bool {
+	return s.caseSensitive
+}
+
+// WithCaseSensitive allows toggling whether the case of the keyspace, table
+// and column names is preserved
+func (s UpdateStatement) WithCaseSensitive(enabled bool) UpdateStatement {
+	s.caseSensitive = enabled
+	return s
+}
+
 // DeleteStatement represents a DELETE query to delete some data in C*
 // It satisfies the Statement interface
 type DeleteStatement struct {
-	keyspace string     // name of the keyspace
-	table    string     // name of the table
-	where    []Relation // where filter clauses
-	keys     Keys       // partition / clustering keys for table
+	keyspace      string         // name of the keyspace
+	table         string         // name of the table
+	columns       []DeleteColumn // columns to be deleted, whole rows if empty
+	where         []Relation     // where filter clauses
+	keys          Keys           // partition / clustering keys for table
+	caseSensitive bool           // whether the case of identifiers is preserved
 }
 
 // NewDeleteStatement adds the ability to craft a new DeleteStatement
@@ -438,12 +589,21 @@ func (s DeleteStatement) Values() []interface{} {
 
 // QueryAndValues returns the CQL query and any bind values
 func (s DeleteStatement) QueryAndValues() (string, []interface{}) {
-	query := fmt.Sprintf("DELETE FROM %s.%s", s.Keyspace(), s.Table())
-	whereCQL, whereValues := generateWhereCQL(s.Relations())
+	query := "DELETE "
+	values := make([]interface{}, 0)
+	if len(s.Columns()) > 0 {
+		columnsCQL, columnValues := generateDeleteColumnsCQL(s.Columns(), s.caseSensitive)
+		query += columnsCQL + " "
+		values = append(values, columnValues...)
+	}
+
+	query += "FROM " + tableIdentifier(s.Keyspace(), s.Table(), s.caseSensitive)
+	whereCQL, whereValues := generateWhereCQL(s.Relations(), s.caseSensitive)
 	if whereCQL != "" {
 		query += " WHERE " + whereCQL
+		values = append(values, whereValues...)
 	}
-	return query, whereValues
+	return query, values
 }
 
 // Keyspace returns the name of the Keyspace for the statement
@@ -456,6 +616,19 @@ func (s DeleteStatement) Table() string {
 	return s.table
 }
 
+// Columns returns the columns (or collection elements) to be deleted. If
+// there are none, the whole rows are deleted
+func (s DeleteStatement) Columns() []DeleteColumn {
+	return s.columns
+}
+
+// WithColumns sets the columns (or collection elements) to be deleted,
+// instead of the whole rows
+func (s DeleteStatement) WithColumns(columns ...DeleteColumn) DeleteStatement {
+	s.columns = columns
+	return s
+}
+
 // Relations provides the WHERE clause Relation items used to evaluate
 // this query
 func (s DeleteStatement) Relations() []Relation {
@@ -467,6 +640,66 @@ func (s DeleteStatement) Keys() Keys {
 	return s.keys
 }
 
+// CaseSensitive returns whether the case of the keyspace, table and column
+// names is preserved (by quoting them) rather than lowercased
+func (s DeleteStatement) CaseSensitive() bool {
+	return s.caseSensitive
+}
+
+// WithCaseSensitive allows toggling whether the case of the keyspace, table
+// and column names is preserved
+func (s DeleteStatement) WithCaseSensitive(enabled bool) DeleteStatement {
+	s.caseSensitive = enabled
+	return s
+}
+
+// AggregateFunc is a CQL aggregate function which can be applied to the
+// fields of a SelectStatement
+type AggregateFunc string
+
+const (
+	AggregateCount AggregateFunc = "COUNT" // number of rows, or of non null values
+	AggregateMin   AggregateFunc = "MIN"   // smallest value
+	AggregateMax   AggregateFunc = "MAX"   // largest value
+	AggregateSum   AggregateFunc = "SUM"   // sum of the values
+	AggregateAvg   AggregateFunc = "AVG"   // average of the values
+)
+
+// DeleteColumn selects a column to be deleted by a DeleteStatement, or a
+// single element of a map or list column
+type DeleteColumn struct {
+	field      string
+	element    interface{} // map key or list index
+	hasElement bool
+}
+
+// Column selects a whole column to be deleted
+func Column(field string) DeleteColumn {
+	return DeleteColumn{field: field}
+}
+
+// MapKey selects the element with the given key of a map column to be deleted
+func MapKey(field string, key interface{}) DeleteColumn {
+	return DeleteColumn{field: field, element: key, hasElement: true}
+}
+
+// ListIndex selects the element at the given index of a list column to be
+// deleted
+func ListIndex(field string, index int) DeleteColumn {
+	return DeleteColumn{field: field, element: index, hasElement: true}
+}
+
+// Field provides the name of the column
+func (c DeleteColumn) Field() string {
+	return c.field
+}
+
+// Element provides the map key or list index of the element to be deleted,
+// and false if the whole column is deleted
+func (c DeleteColumn) Element() (interface{}, bool) {
+	return c.element, c.hasElement
+}
+
 // cqlStatement represents a statement that executes raw CQL
 type cqlStatement struct {
 	query  string
@@ -485,55 +718,105 @@ func (_ noOpStatement) Query() string { return "" }
 
 func (_ noOpStatement) Values() []interface{} { return []interface{}{} }
 
+// generateSelectorsCQL generates the comma separated list of selected fields,
+// with the aggregate function applied if there is one. An expected output
+// may be something like:
+//	- "foo, bar"
+//	- "COUNT(*)"
+//	- "MAX(foo)"
+func generateSelectorsCQL(fields []string, fn AggregateFunc, caseSensitive bool) string {
+	selectors := make([]string, len(fields))
+	for i, field := range fields {
+		selector := field
+		if field != "*" {
+			selector = columnName(field, caseSensitive)
+		}
+		if fn != "" {
+			selector = fmt.Sprintf("%s(%s)", fn, selector)
+		}
+		selectors[i] = selector
+	}
+	return strings.Join(selectors, ", ")
+}
+
 // generateUpdateSetCQL takes in a field map and generates the comma separated
 // SET syntax. An expected output may be something like:
 // 	- "foo = ?", {1}
 // 	- "foo = ?, bar = ?", {1, 2}
-func generateUpdateSetCQL(fm map[string]interface{}) (string, []interface{}) {
+func generateUpdateSetCQL(fm map[string]interface{}, caseSensitive bool) (string, []interface{}) {
 	clauses, values := make([]string, 0, len(fm)), make([]interface{}, 0, len(fm))
 	for _, fieldName := range sortedKeys(fm) {
 		value := fm[fieldName]
+		column := columnName(fieldName, caseSensitive)
 		if modifier, ok := value.(Modifier); ok {
-			stmt, vals := modifier.cql(fieldName)
+			stmt, vals := modifier.cql(column)
 			clauses = append(clauses, stmt)
 			values = append(values, vals...)
 			continue
 		}
-		clauses = append(clauses, fieldName+" = ?")
+		clauses = append(clauses, column+" = ?")
 		values = append(values, value)
 	}
 	return strings.Join(clauses, ", "), values
 }
 
+// generateDeleteColumnsCQL generates the comma separated list of columns
+// to be deleted. An expected output may be something like:
+//	- "foo, bar", {}
+//	- "foo, bar[?]", {"key"}
+func generateDeleteColumnsCQL(columns []DeleteColumn, caseSensitive bool) (string, []interface{}) {
+	clauses, values := make([]string, 0, len(columns)), make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		clause := columnName(column.Field(), caseSensitive)
+		if element, ok := column.Element(); ok {
+			clause += "[?]"
+			values = append(values, element)
+		}
+		clauses = append(clauses, clause)
+	}
+	return strings.Join(clauses, ", "), values
+}
+
 // generateWhereCQL takes a list of relations and generates the CQL for
 // a WHERE clause. An expected output may be something like:
 //	- "foo = ?", {1}
 //	- "foo = ? AND bar IN ?", {1, {"a", "b", "c"}}
-func generateWhereCQL(rs []Relation) (string, []interface{}) {
+//	- "foo = ? AND (bar, baz) > (?, ?)", {1, 2, 3}
+func generateWhereCQL(rs []Relation, caseSensitive bool) (string, []interface{}) {
 	clauses, values := make([]string, 0, len(rs)), make([]interface{}, 0, len(rs))
 	for _, relation := range rs {
-		clause, bindValue := generateRelationCQL(relation)
+		clause, bindValues := generateRelationCQL(relation, caseSensitive)
 		clauses = append(clauses, clause)
-		values = append(values, bindValue)
+		values = append(values, bindValues...)
 	}
 	return strings.Join(clauses, " AND "), values
 }
 
-func generateRelationCQL(rel Relation) (string, interface{}) {
-	field := strings.ToLower(rel.Field())
+func generateRelationCQL(rel Relation, caseSensitive bool) (string, []interface{}) {
+	if rel.IsTuple() {
+		return generateTupleRelationCQL(rel, caseSensitive)
+	}
+
+	field := columnName(rel.Field(), caseSensitive)
 	switch rel.Comparator() {
 	case CmpEquality:
-		return field + " = ?", rel.Terms()[0]
+		return field + " = ?", rel.Terms()[:1]
 	case CmpIn:
-		return field + " IN ?", rel.Terms()
+		return field + " IN ?", toI(rel.Terms())
 	case CmpGreaterThan:
-		return field + " > ?", rel.Terms()[0]
+		return field + " > ?", rel.Terms()[:1]
 	case CmpGreaterThanOrEquals:
-		return field + " >= ?", rel.Terms()[0]
+		return field + " >= ?", rel.Terms()[:1]
 	case CmpLesserThan:
-		return field + " < ?", rel.Terms()[0]
+		return field + " < ?", rel.Terms()[:1]
 	case CmpLesserThanOrEquals:
-		return field + " <= ?", rel.Terms()[0]
+		return field + " <= ?", rel.Terms()[:1]
+	case CmpContains:
+		return field + " CONTAINS ?", rel.Terms()[:1]
+	case CmpContainsKey:
+		return field + " CONTAINS KEY ?", rel.Terms()[:1]
+	case CmpNotEquals:
+		return field + " != ?", rel.Terms()[:1]
 	default:
 		// This represents an invalid Comparator and would only manifest
 		// if we've initialised a Relation incorrectly within this package
@@ -541,14 +824,36 @@ func generateRelationCQL(rel Relation) (string, interface{}) {
 	}
 }
 
+// generateTupleRelationCQL generates the CQL for a multi-column relation,
+// which binds one value per field, for example "(foo, bar) > (?, ?)"
+func generateTupleRelationCQL(rel Relation, caseSensitive bool) (string, []interface{}) {
+	op := ""
+	switch rel.Comparator() {
+	case CmpGreaterThan:
+		op = ">"
+	case CmpGreaterThanOrEquals:
+		op = ">="
+	case CmpLesserThan:
+		op = "<"
+	case CmpLesserThanOrEquals:
+		op = "<="
+	default:
+		panic(fmt.Sprintf("unsupported tuple comparator %v", rel.Comparator()))
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(rel.Fields())), ", ")
+	fields := strings.Join(columnNames(rel.Fields(), caseSensitive), ", ")
+	return fmt.Sprintf("(%s) %s (%s)", fields, op, placeholders), rel.Terms()
+}
+
 // generateOrderByCQL generates the CQL for the ORDER BY clause. An expected
 // output might look like:
 //	- foo ASC
 //  - foo ASC, bar DESC
-func generateOrderByCQL(order []ClusteringOrderColumn) string {
+func generateOrderByCQL(order []ClusteringOrderColumn, caseSensitive bool) string {
 	out := make([]string, 0, len(order))
 	for _, oc := range order {
-		out = append(out, oc.Column+" "+oc.Direction.String())
+		out = append(out, columnName(oc.Column, caseSensitive)+" "+oc.Direction.String())
 	}
 	return strings.Join(out, ", ")
 }
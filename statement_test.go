@@ -1,6 +1,7 @@
 package gocassa
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -37,6 +38,59 @@ func TestSelectStatement(t *testing.T) {
 	assert.Equal(t, []interface{}{"bar", []interface{}{"bing"}, 10}, stmt.Values())
 }
 
+func TestSelectAggregateStatement(t *testing.T) {
+	keys := Keys{PartitionKeys: []string{"a"}}
+	stmt, err := NewSelectStatement("ks1", "tbl1", []string{"*"}, []Relation{Eq("a", 1)}, keys)
+	assert.NoError(t, err)
+	stmt = stmt.WithAggregate(AggregateCount)
+	assert.Equal(t, "SELECT COUNT(*) FROM ks1.tbl1 WHERE a = ?", stmt.Query())
+
+	stmt, err = NewSelectStatement("ks1", "tbl1", []string{"Price"}, []Relation{Eq("a", 1)}, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT MAX(price) FROM ks1.tbl1 WHERE a = ?", stmt.WithAggregate(AggregateMax).Query())
+
+	stmt, err = NewSelectStatement("ks1", "tbl1", []string{"a", "b"}, nil, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT a, b FROM ks1.tbl1", stmt.WithDistinct(true).Query())
+
+	tbl := NewConnection(nil).KeySpace("ks1").MultimapTable("sale", "SellerId", "Id", typedSale{})
+	var count int64
+	op := tbl.Table().Where(Eq("SellerId", "1")).Count(&count).WithOptions(Options{Limit: 10})
+	assert.Equal(t, "SELECT COUNT(*) FROM ks1.sale_multimap_SellerId_Id WHERE sellerid = ?", op.GenerateStatement().Query())
+	op = tbl.Table().Where(Eq("SellerId", "1")).Aggregate(AggregateSum, "Price", &count)
+	assert.Equal(t, "SELECT SUM(price) FROM ks1.sale_multimap_SellerId_Id WHERE sellerid = ?", op.GenerateStatement().Query())
+	var sales []typedSale
+	op = tbl.Table().DistinctPartitionKeys(&sales)
+	assert.Equal(t, "SELECT DISTINCT sellerid FROM ks1.sale_multimap_SellerId_Id", op.GenerateStatement().Query())
+}
+
+func TestJSONStatements(t *testing.T) {
+	keys := Keys{PartitionKeys: []string{"a"}}
+	sel, err := NewSelectStatement("ks1", "tbl1", []string{"a", "b"}, []Relation{Eq("a", 1)}, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT JSON a, b FROM ks1.tbl1 WHERE a = ?", sel.WithJSON(true).Query())
+
+	row := []byte(`{"a": "b"}`)
+	ins, err := NewInsertJSONStatement("ks1", "tbl1", row, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO ks1.tbl1 JSON ?", ins.Query())
+	assert.Equal(t, []interface{}{`{"a": "b"}`}, ins.Values())
+
+	ins = ins.WithDefaultUnset(true).WithTTL(time.Hour)
+	assert.Equal(t, "INSERT INTO ks1.tbl1 JSON ? DEFAULT UNSET USING TTL ?", ins.Query())
+	assert.Equal(t, []interface{}{`{"a": "b"}`, 3600}, ins.Values())
+
+	_, err = NewInsertJSONStatement("ks1", "tbl1", nil, keys)
+	assert.Error(t, err)
+
+	tbl := NewConnection(nil).KeySpace("ks1").MapTable("sale", "Id", typedSale{})
+	var rows []json.RawMessage
+	op := tbl.Table().Where(Eq("Id", "1")).ReadJSON(&rows)
+	assert.Equal(t, "SELECT JSON created, id, price, sellerid FROM ks1.sale_map_Id WHERE id = ?", op.GenerateStatement().Query())
+	op = tbl.Table().SetJSON(row).WithOptions(Options{DefaultUnset: true})
+	assert.Equal(t, "INSERT INTO ks1.sale_map_Id JSON ? DEFAULT UNSET", op.GenerateStatement().Query())
+}
+
 func TestInsertStatement(t *testing.T) {
 	fieldMap := map[string]interface{}{"a": "b"}
 	keys := Keys{PartitionKeys: []string{"a"}}
@@ -70,6 +124,18 @@ func TestUpdateStatement(t *testing.T) {
 	assert.Equal(t, "UPDATE ks1.tbl1 SET a = ?, c = c + ? WHERE foo = ?", stmt.Query())
 	assert.Equal(t, []interface{}{"b", []interface{}{"d"}, "bar"}, stmt.Values())
 
+	fieldMap = map[string]interface{}{"a": SetAdd("d", "e"), "c": SetRemove("f")}
+	stmt, err = NewUpdateStatement("ks1", "tbl1", fieldMap, relations, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE ks1.tbl1 SET a = a + ?, c = c - ? WHERE foo = ?", stmt.Query())
+	assert.Equal(t, []interface{}{[]interface{}{"d", "e"}, []interface{}{"f"}, "bar"}, stmt.Values())
+
+	fieldMap = map[string]interface{}{"a": MapRemoveKeys("d", "e"), "b": ListAppendAll(1, 2), "c": ListPrependAll(3, 4)}
+	stmt, err = NewUpdateStatement("ks1", "tbl1", fieldMap, relations, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE ks1.tbl1 SET a = a - ?, b = b + ?, c = ? + c WHERE foo = ?", stmt.Query())
+	assert.Equal(t, []interface{}{[]interface{}{"d", "e"}, []interface{}{1, 2}, []interface{}{3, 4}, "bar"}, stmt.Values())
+
 	fieldMap = map[string]interface{}{"a": "b", "c": "d"}
 	stmt, err = NewUpdateStatement("ks1", "tbl1", fieldMap, relations, keys)
 	assert.NoError(t, err)
@@ -106,69 +172,170 @@ func TestDeleteStatement(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "DELETE FROM ks1.tbl1 WHERE foo = ? AND baz IN ?", stmt.Query())
 	assert.Equal(t, []interface{}{"bar", []interface{}{"a", "b", "c"}}, stmt.Values())
+
+	stmt = stmt.WithColumns(Column("Name"), MapKey("scores", "x"), ListIndex("tags", 2))
+	assert.Equal(t, "DELETE name, scores[?], tags[?] FROM ks1.tbl1 WHERE foo = ? AND baz IN ?", stmt.Query())
+	assert.Equal(t, []interface{}{"x", 2, "bar", []interface{}{"a", "b", "c"}}, stmt.Values())
+
+	relations = []Relation{
+		Eq("foo", "bar"),
+		GTE("baz", 1),
+		LT("baz", 5),
+	}
+	stmt, err = NewDeleteStatement("ks1", "tbl1", relations, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM ks1.tbl1 WHERE foo = ? AND baz >= ? AND baz < ?", stmt.Query())
+	assert.Equal(t, []interface{}{"bar", 1, 5}, stmt.Values())
 }
 
 func TestGenerateWhereCQL(t *testing.T) {
 	stmt, values := generateWhereCQL([]Relation{
 		Eq("foo", "bar"),
-	})
+	}, false)
 	assert.Equal(t, "foo = ?", stmt)
 	assert.Equal(t, []interface{}{"bar"}, values)
 
 	stmt, values = generateWhereCQL([]Relation{
 		Eq("foo", "bar"),
 		In("baz", "a", "b", "c"),
-	})
+	}, false)
 	assert.Equal(t, "foo = ? AND baz IN ?", stmt)
 	assert.Equal(t, []interface{}{"bar", []interface{}{"a", "b", "c"}}, values)
+
+	stmt, values = generateWhereCQL([]Relation{
+		Eq("foo", "bar"),
+		TupleGTE([]string{"baz", "bing"}, 1, 2),
+		TupleLT([]string{"baz", "bing"}, 3, 4),
+	}, false)
+	assert.Equal(t, "foo = ? AND (baz, bing) >= (?, ?) AND (baz, bing) < (?, ?)", stmt)
+	assert.Equal(t, []interface{}{"bar", 1, 2, 3, 4}, values)
 }
 
 func TestGenerateRelationCQL(t *testing.T) {
-	stmt, value := generateRelationCQL(Eq("foo", "bar"))
+	stmt, value := generateRelationCQL(Eq("foo", "bar"), false)
 	assert.Equal(t, "foo = ?", stmt)
-	assert.Equal(t, "bar", value)
+	assert.Equal(t, []interface{}{"bar"}, value)
 
-	stmt, value = generateRelationCQL(Eq("FoO", "BAR"))
+	stmt, value = generateRelationCQL(Eq("FoO", "BAR"), false)
 	assert.Equal(t, "foo = ?", stmt)
-	assert.Equal(t, "BAR", value)
+	assert.Equal(t, []interface{}{"BAR"}, value)
 
-	stmt, value = generateRelationCQL(In("foo", "a", "b", "c"))
+	stmt, value = generateRelationCQL(In("foo", "a", "b", "c"), false)
 	assert.Equal(t, "foo IN ?", stmt)
-	assert.Equal(t, []interface{}{"a", "b", "c"}, value)
+	assert.Equal(t, []interface{}{[]interface{}{"a", "b", "c"}}, value)
 
-	stmt, value = generateRelationCQL(GT("foo", 1))
+	stmt, value = generateRelationCQL(GT("foo", 1), false)
 	assert.Equal(t, "foo > ?", stmt)
-	assert.Equal(t, 1, value)
+	assert.Equal(t, []interface{}{1}, value)
 
-	stmt, value = generateRelationCQL(GTE("foo", 1))
+	stmt, value = generateRelationCQL(GTE("foo", 1), false)
 	assert.Equal(t, "foo >= ?", stmt)
-	assert.Equal(t, 1, value)
+	assert.Equal(t, []interface{}{1}, value)
 
-	stmt, value = generateRelationCQL(LT("foo", 1))
+	stmt, value = generateRelationCQL(LT("foo", 1), false)
 	assert.Equal(t, "foo < ?", stmt)
-	assert.Equal(t, 1, value)
+	assert.Equal(t, []interface{}{1}, value)
 
-	stmt, value = generateRelationCQL(LTE("foo", 1))
+	stmt, value = generateRelationCQL(LTE("foo", 1), false)
 	assert.Equal(t, "foo <= ?", stmt)
-	assert.Equal(t, 1, value)
+	assert.Equal(t, []interface{}{1}, value)
+
+	stmt, value = generateRelationCQL(Contains("foo", "a"), false)
+	assert.Equal(t, "foo CONTAINS ?", stmt)
+	assert.Equal(t, []interface{}{"a"}, value)
+
+	stmt, value = generateRelationCQL(ContainsKey("foo", "a"), false)
+	assert.Equal(t, "foo CONTAINS KEY ?", stmt)
+	assert.Equal(t, []interface{}{"a"}, value)
+
+	stmt, value = generateRelationCQL(NotEq("foo", 1), false)
+	assert.Equal(t, "foo != ?", stmt)
+	assert.Equal(t, []interface{}{1}, value)
+
+	stmt, value = generateRelationCQL(TupleGT([]string{"Foo", "order"}, 1, "a"), false)
+	assert.Equal(t, `(foo, "order") > (?, ?)`, stmt)
+	assert.Equal(t, []interface{}{1, "a"}, value)
+
+	stmt, value = generateRelationCQL(TupleLTE([]string{"foo", "bar"}, 1, "a"), false)
+	assert.Equal(t, "(foo, bar) <= (?, ?)", stmt)
+	assert.Equal(t, []interface{}{1, "a"}, value)
 
 	assert.PanicsWithValue(t, "unknown comparator -1", func() {
-		stmt, value = generateRelationCQL(Relation{cmp: -1})
+		stmt, value = generateRelationCQL(Relation{cmp: -1}, false)
+	})
+	assert.Panics(t, func() {
+		TupleGT([]string{"foo", "bar"}, 1)
 	})
 }
 
 func TestGenerateOrderByCQL(t *testing.T) {
-	stmt := generateOrderByCQL([]ClusteringOrderColumn{})
+	stmt := generateOrderByCQL([]ClusteringOrderColumn{}, false)
 	assert.Equal(t, "", stmt)
 
 	stmt = generateOrderByCQL([]ClusteringOrderColumn{
 		{Column: "foo", Direction: ASC},
-	})
+	}, false)
 	assert.Equal(t, "foo ASC", stmt)
 
 	stmt = generateOrderByCQL([]ClusteringOrderColumn{
 		{Column: "foo", Direction: ASC},
 		{Column: "bar", Direction: DESC},
-	})
+	}, false)
 	assert.Equal(t, "foo ASC, bar DESC", stmt)
 }
+
+func TestQuotedIdentifiers(t *testing.T) {
+	keys := Keys{PartitionKeys: []string{"UserId"}, ClusteringColumns: []string{"Order"}}
+	rels := []Relation{Eq("UserId", "a"), GT("Order", 1)}
+
+	sel, err := NewSelectStatement("ks1", "Events", []string{"UserId", "Order", "key"}, rels, keys)
+	assert.NoError(t, err)
+	sel = sel.WithOrderBy([]ClusteringOrderColumn{{Column: "Order", Direction: DESC}})
+	assert.Equal(t, `SELECT userid, "order", "key" FROM ks1.Events WHERE userid = ? AND "order" > ? ORDER BY "order" DESC`, sel.Query())
+	sel = sel.WithCaseSensitive(true)
+	assert.Equal(t, `SELECT "UserId", "Order", "key" FROM ks1."Events" WHERE "UserId" = ? AND "Order" > ? ORDER BY "Order" DESC`, sel.Query())
+
+	ins, err := NewInsertStatement("select", "tbl1", map[string]interface{}{"UserId": "a", "Order": 1}, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "select".tbl1 ("order", userid) VALUES (?, ?)`, ins.Query())
+	assert.Equal(t, `INSERT INTO "select".tbl1 ("Order", "UserId") VALUES (?, ?)`, ins.WithCaseSensitive(true).Query())
+
+	upd, err := NewUpdateStatement("ks1", "tbl1", map[string]interface{}{"Key": "b", "Tags": SetAdd("c")}, rels, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE ks1.tbl1 SET "key" = ?, tags = tags + ? WHERE userid = ? AND "order" > ?`, upd.Query())
+	assert.Equal(t, `UPDATE ks1.tbl1 SET "Key" = ?, "Tags" = "Tags" + ? WHERE "UserId" = ? AND "Order" > ?`, upd.WithCaseSensitive(true).Query())
+
+	del, err := NewDeleteStatement("ks1", "tbl1", rels, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, `DELETE FROM ks1.tbl1 WHERE userid = ? AND "order" > ?`, del.Query())
+	assert.Equal(t, `DELETE FROM ks1.tbl1 WHERE "UserId" = ? AND "Order" > ?`, del.WithCaseSensitive(true).Query())
+}
+
+func TestCaseSensitiveTable(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").MapTable("sale", "Id", typedSale{}).
+		WithOptions(Options{TableName: "Sales", CaseSensitive: true})
+	row := typedSale{Id: "1", SellerId: "2"}
+	stmt := tbl.Set(row).GenerateStatement()
+	assert.Equal(t, `UPDATE ks1."Sales" SET "Created" = ?, "Price" = ?, "SellerId" = ? WHERE "Id" = ?`, stmt.Query())
+
+	stmt = tbl.Read("1", &row).GenerateStatement()
+	assert.Equal(t, `SELECT "Created", "Id", "Price", "SellerId" FROM ks1."Sales" WHERE "Id" = ?`, stmt.Query())
+}
+
+func TestCaseSensitiveRecipeTable(t *testing.T) {
+	// The names generated for recipe tables were always created unquoted, and
+	// so are stored lowercased
+	ks := NewConnection(nil).KeySpace("ks1")
+	tbl := ks.MultimapTable("sale", "SellerId", "Id", typedSale{}).WithOptions(Options{CaseSensitive: true})
+	row := typedSale{Id: "1", SellerId: "2"}
+	stmt := tbl.Read("2", "1", &row).GenerateStatement()
+	assert.Equal(t, `SELECT "Created", "Id", "Price", "SellerId" FROM ks1.sale_multimap_sellerid_id WHERE "SellerId" = ? AND "Id" = ?`, stmt.Query())
+
+	create, err := tbl.CreateStatement()
+	assert.NoError(t, err)
+	assert.Contains(t, create.Query(), "CREATE TABLE ks1.sale_multimap_sellerid_id (")
+
+	mkTbl := ks.MultimapMultiKeyTable("store", StorePK, StoreIndex, Store{}).WithOptions(Options{CaseSensitive: true})
+	stmt = mkTbl.Delete(map[string]interface{}{CityKey: "London"}, map[string]interface{}{ManagerKey: "Joe", IdKey: "1"}).GenerateStatement()
+	assert.Equal(t, `DELETE FROM ks1.store_multimapmk WHERE "City" = ? AND "Manager" = ? AND "Id" = ?`, stmt.Query())
+}
@@ -22,6 +22,7 @@ type tableInfo struct {
 	fieldNames     map[string]struct{} // This is here only to check containment
 	fields         []string
 	fieldValues    []interface{}
+	fieldOptions   map[string]columnOptions
 }
 
 func newTableInfo(keyspace, name string, keys Keys, entity interface{}, fieldSource map[string]interface{}) *tableInfo {
@@ -31,6 +32,7 @@ func newTableInfo(keyspace, name string, keys Keys, entity interface{}, fieldSou
 		marshalSource: entity,
 		keys:          keys,
 		fieldSource:   fieldSource,
+		fieldOptions:  fieldColumnOptions(entity),
 	}
 	fields := make([]string, 0, len(fieldSource))
 	values := make([]interface{}, 0, len(fieldSource))
@@ -65,10 +67,12 @@ func (t t) Where(rs ...Relation) Filter {
 	}
 }
 
-func (t t) generateFieldList(sel []string) []string {
+func (t t) generateFieldList(sel []string, caseSensitive bool) []string {
 	xs := make([]string, len(t.info.fields))
 	if len(sel) > 0 {
 		xs = sel
+	} else if caseSensitive {
+		copy(xs, t.info.fields)
 	} else {
 		for i, v := range t.info.fields {
 			xs[i] = strings.ToLower(v)
@@ -85,15 +89,19 @@ func relations(keys Keys, m map[string]interface{}) []Relation {
 	return ret
 }
 
-func removeFields(m map[string]interface{}, s []string) map[string]interface{} {
+func removeFields(m map[string]interface{}, s []string, caseSensitive bool) map[string]interface{} {
 	keys := map[string]bool{}
 	for _, v := range s {
-		v = strings.ToLower(v)
+		if !caseSensitive {
+			v = strings.ToLower(v)
+		}
 		keys[v] = true
 	}
 	ret := map[string]interface{}{}
 	for k, v := range m {
-		k = strings.ToLower(k)
+		if !caseSensitive {
+			k = strings.ToLower(k)
+		}
 		if !keys[k] {
 			ret[k] = v
 		}
@@ -135,7 +143,7 @@ func (t t) Set(i interface{}) Op {
 		panic("SetWithOptions: Incompatible type")
 	}
 	ks := append(t.info.keys.PartitionKeys, t.info.keys.ClusteringColumns...)
-	updFields := removeFields(m, ks)
+	updFields := removeFields(m, ks, t.options.CaseSensitive)
 	if len(updFields) == 0 || allFieldValuesAreNullable(updFields) {
 		return newWriteOp(t.keySpace.qe, filter{t: t}, insertOpType, m)
 	}
@@ -147,7 +155,30 @@ func (t t) Set(i interface{}) Op {
 	}, updateOpType, updFields)
 }
 
+func (t t) SetJSON(row []byte) Op {
+	op := newWriteOp(t.keySpace.qe, filter{t: t}, insertOpType, nil)
+	op.json = row
+	return op
+}
+
+func (t t) DistinctPartitionKeys(pointerToASlice interface{}) Op {
+	return &singleOp{
+		qe:       t.keySpace.qe,
+		f:        filter{t: t},
+		opType:   readOpType,
+		result:   pointerToASlice,
+		distinct: true}
+}
+
 func (t t) Create() error {
+	// The name is validated before the types are created, so that an invalid
+	// name doesn't leave them behind
+	if err := validateTableName(t.Name()); err != nil {
+		return err
+	}
+	if err := t.createTypes(); err != nil {
+		return err
+	}
 	if stmt, err := t.CreateStatement(); err != nil {
 		return err
 	} else {
@@ -156,6 +187,12 @@ func (t t) Create() error {
 }
 
 func (t t) CreateIfNotExist() error {
+	if err := validateTableName(t.Name()); err != nil {
+		return err
+	}
+	if err := t.createTypes(); err != nil {
+		return err
+	}
 	if stmt, err := t.CreateIfNotExistStatement(); err != nil {
 		return err
 	} else {
@@ -163,9 +200,27 @@ func (t t) CreateIfNotExist() error {
 	}
 }
 
+// createTypes creates any user-defined types used by the table which do not
+// exist yet
+func (t t) createTypes() error {
+	stmts, err := t.CreateTypeStatements()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if err := t.keySpace.qe.Execute(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t t) Recreate() error {
-	if ex, err := t.keySpace.Exists(t.Name()); ex && err == nil {
-		if err := t.keySpace.DropTable(t.Name()); err != nil {
+	if err := validateTableName(t.Name()); err != nil {
+		return err
+	}
+	if ex, err := t.keySpace.Exists(t.cqlName(t.options.CaseSensitive)); ex && err == nil {
+		if err := t.keySpace.dropTable(t.cqlName(t.options.CaseSensitive), t.options.CaseSensitive); err != nil {
 			return err
 		}
 	} else if err != nil {
@@ -176,32 +231,40 @@ func (t t) Recreate() error {
 
 func (t t) CreateStatement() (Statement, error) {
 	return createTable(t.keySpace.name,
-		t.Name(),
+		t.cqlName(t.options.CaseSensitive),
 		t.info.keys.PartitionKeys,
 		t.info.keys.ClusteringColumns,
 		t.info.fields,
 		t.info.fieldValues,
+		t.info.fieldOptions,
 		t.options.ClusteringOrder,
 		t.info.keys.Compound,
 		t.options.CompactStorage,
 		t.options.Compressor,
+		t.options.CaseSensitive,
 	)
 }
 
 func (t t) CreateIfNotExistStatement() (Statement, error) {
 	return createTableIfNotExist(t.keySpace.name,
-		t.Name(),
+		t.cqlName(t.options.CaseSensitive),
 		t.info.keys.PartitionKeys,
 		t.info.keys.ClusteringColumns,
 		t.info.fields,
 		t.info.fieldValues,
+		t.info.fieldOptions,
 		t.options.ClusteringOrder,
 		t.info.keys.Compound,
 		t.options.CompactStorage,
 		t.options.Compressor,
+		t.options.CaseSensitive,
 	)
 }
 
+func (t t) CreateTypeStatements() ([]Statement, error) {
+	return createTypesIfNotExist(t.keySpace.name, t.info.fields, t.info.fieldValues, t.info.fieldOptions)
+}
+
 func (t t) Name() string {
 	if len(t.options.TableName) > 0 {
 		return t.options.TableName
@@ -209,10 +272,41 @@ func (t t) Name() string {
 	return t.info.name
 }
 
-func (table t) WithOptions(o Options) Table {
-	return t{
+// cqlName returns the name of the table in statements. Only a TableName option
+// keeps its case with caseSensitive set: the names generated by the keyspace
+// for its tables and recipes, such as "sale_multimap_SellerId_Id", were always
+// created unquoted, so Cassandra stores them lowercased.
+func (t t) cqlName(caseSensitive bool) string {
+	if caseSensitive && len(t.options.TableName) == 0 {
+		return strings.ToLower(t.info.name)
+	}
+	return t.Name()
+}
+
+func (table t) Options() Options {
+	return table.options
+}
+
+func (table *t) WithOptions(o Options) Table {
+	result := &t{
 		keySpace: table.keySpace,
 		info:     table.info,
 		options:  table.options.Merge(o),
 	}
+	reregister(table, result)
+	return result
+}
+
+func (table *t) registry() *tableRegistry {
+	return &table.keySpace.registry
+}
+
+func (table t) withColumnType(field, cqlType string) Table {
+	info := *table.info
+	info.fieldOptions = overrideColumnType(table.info.fieldOptions, field, cqlType)
+	return &t{
+		keySpace: table.keySpace,
+		info:     &info,
+		options:  table.options,
+	}
 }
@@ -1,6 +1,7 @@
 package gocassa
 
 import (
+	"context"
 	"time"
 )
 
@@ -22,6 +23,9 @@ func (o *timeSeriesT) CreateStatement() (Statement, error) { return o.Table().Cr
 func (o *timeSeriesT) CreateIfNotExistStatement() (Statement, error) {
 	return o.Table().CreateIfNotExistStatement()
 }
+func (o *timeSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
 
 func (o *timeSeriesT) Set(v interface{}) Op {
 	m, ok := toMap(v)
@@ -31,13 +35,13 @@ func (o *timeSeriesT) Set(v interface{}) Op {
 	if tim, ok := m[o.timeField].(time.Time); !ok {
 		panic("timeField is not actually a time.Time")
 	} else {
-		m[bucketFieldName] = bucket(tim, o.bucketSize)
+		m[bucketFieldName] = o.bucketing().Bucket(tim)
 	}
 	return o.Table().Set(m)
 }
 
 func (o *timeSeriesT) Update(timeStamp time.Time, id interface{}, m map[string]interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
 			Eq(o.timeField, timeStamp),
@@ -45,8 +49,12 @@ func (o *timeSeriesT) Update(timeStamp time.Time, id interface{}, m map[string]i
 		Update(m)
 }
 
+func (o *timeSeriesT) UpdateStatic(timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(timeStamp).Filter().Update(m)
+}
+
 func (o *timeSeriesT) Delete(timeStamp time.Time, id interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
 			Eq(o.timeField, timeStamp),
@@ -55,7 +63,7 @@ func (o *timeSeriesT) Delete(timeStamp time.Time, id interface{}) Op {
 }
 
 func (o *timeSeriesT) Read(timeStamp time.Time, id, pointer interface{}) Op {
-	bucket := bucket(timeStamp, o.bucketSize)
+	bucket := o.bucketing().Bucket(timeStamp)
 	return o.Table().
 		Where(Eq(bucketFieldName, bucket),
 			Eq(o.timeField, timeStamp),
@@ -64,30 +72,38 @@ func (o *timeSeriesT) Read(timeStamp time.Time, id, pointer interface{}) Op {
 }
 
 func (o *timeSeriesT) List(startTime time.Time, endTime time.Time, pointerToASlice interface{}) Op {
-	buckets := []interface{}{}
-	for bucket := o.Buckets(startTime); bucket.Bucket().Before(endTime); bucket = bucket.Next() {
-		buckets = append(buckets, bucket.Bucket())
-	}
-	return o.Table().
-		Where(In(bucketFieldName, buckets...),
-			GTE(o.timeField, startTime),
-			LTE(o.timeField, endTime)).
-		Read(pointerToASlice)
+	return newBucketsOp(o.Buckets(startTime), o.Buckets(endTime), endTime,
+		[]Relation{GTE(o.timeField, startTime), LTE(o.timeField, endTime)},
+		pointerToASlice)
+}
+
+func (o *timeSeriesT) ListLatest(start, before time.Time, limit int, pointerToASlice interface{}) Op {
+	return newLatestOp(o.Buckets(start), o.Buckets(before), o.timeField, start, before, limit, pointerToASlice)
+}
+
+func (o *timeSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
 }
 
 func (o *timeSeriesT) Buckets(start time.Time) Buckets {
 	return bucketIter{
 		v:         start,
-		step:      o.bucketSize,
+		bucketing: o.bucketing(),
 		field:     bucketFieldName,
 		invariant: o.Table().Where()}
 }
 
+func (o *timeSeriesT) Retention(ctx context.Context, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(start), cutoff)
+}
+
 func (o *timeSeriesT) WithOptions(opt Options) TimeSeriesTable {
-	return &timeSeriesT{
+	result := &timeSeriesT{
 		t:          o.Table().WithOptions(opt),
 		timeField:  o.timeField,
 		idField:    o.idField,
 		bucketSize: o.bucketSize,
 	}
+	reregister(o, result)
+	return result
 }
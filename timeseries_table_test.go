@@ -51,6 +51,34 @@ func TestTimeSeriesTable(t *testing.T) {
 	assert.Len(t, ts, 4)
 }
 
+func TestTimeSeriesTableListLatestStatement(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").TimeSeriesTable("trip", "Time", "Id", time.Hour, Trip{})
+	trips := []Trip{}
+	op := tbl.ListLatest(parse("2006 Jan 2 10:00:00"), parse("2006 Jan 2 15:04:05"), 50, &trips)
+	stmt := op.GenerateStatement()
+	assert.Equal(t, "SELECT id, time, bucket FROM ks1.trip_timeSeries_Time_Id_1h0m0s WHERE bucket = ? AND time >= ? AND time < ? ORDER BY time DESC LIMIT ?", stmt.Query())
+	require.Len(t, stmt.Values(), 4)
+	assert.True(t, parse("2006 Jan 2 15:00:00").Equal(stmt.Values()[0].(time.Time)))
+	assert.Equal(t, 50, stmt.Values()[3])
+}
+
+func TestTimeSeriesTableBucketQueryStatement(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").TimeSeriesTable("trip", "Time", "Id", time.Hour, Trip{})
+	trips := []Trip{}
+
+	// Narrow ranges are read with a single query by default, and wide ones bucket by bucket
+	op := tbl.List(parse("2006 Jan 2 10:00:00"), parse("2006 Jan 2 15:04:05"), &trips)
+	assert.Equal(t, "SELECT id, time, bucket FROM ks1.trip_timeSeries_Time_Id_1h0m0s WHERE bucket IN ? AND time >= ? AND time <= ?", op.GenerateStatement().Query())
+	assert.Len(t, op.GenerateStatement().Values()[0], 6)
+	op = tbl.List(parse("2006 Jan 1 10:00:00"), parse("2006 Jan 2 15:04:05"), &trips)
+	assert.Equal(t, "SELECT id, time, bucket FROM ks1.trip_timeSeries_Time_Id_1h0m0s WHERE bucket = ? AND time >= ? AND time <= ?", op.GenerateStatement().Query())
+
+	op = op.WithOptions(Options{BucketQuery: InBucketQuery})
+	assert.Len(t, op.GenerateStatement().Values()[0], 30)
+	op = tbl.WithOptions(Options{BucketQuery: SequentialBucketQuery}).List(parse("2006 Jan 2 10:00:00"), parse("2006 Jan 2 15:04:05"), &trips)
+	assert.Equal(t, "SELECT id, time, bucket FROM ks1.trip_timeSeries_Time_Id_1h0m0s WHERE bucket = ? AND time >= ? AND time <= ?", op.GenerateStatement().Query())
+}
+
 func TestOptions(t *testing.T) {
 	tbl := ns.TimeSeriesTable("tripTime6", "Time", "Id", 2*time.Hour, Trip{})
 	createIf(tbl.(TableChanger), t)
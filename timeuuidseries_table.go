@@ -0,0 +1,153 @@
+package gocassa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocql/gocql"
+)
+
+type timeUUIDSeriesT struct {
+	t          Table
+	idField    string
+	bucketSize time.Duration
+}
+
+func (o *timeUUIDSeriesT) Table() Table                        { return o.t }
+func (o *timeUUIDSeriesT) Create() error                       { return o.Table().Create() }
+func (o *timeUUIDSeriesT) CreateIfNotExist() error             { return o.Table().CreateIfNotExist() }
+func (o *timeUUIDSeriesT) Name() string                        { return o.Table().Name() }
+func (o *timeUUIDSeriesT) Recreate() error                     { return o.Table().Recreate() }
+func (o *timeUUIDSeriesT) CreateStatement() (Statement, error) { return o.Table().CreateStatement() }
+func (o *timeUUIDSeriesT) CreateIfNotExistStatement() (Statement, error) {
+	return o.Table().CreateIfNotExistStatement()
+}
+func (o *timeUUIDSeriesT) CreateTypeStatements() ([]Statement, error) {
+	return o.Table().CreateTypeStatements()
+}
+
+func (o *timeUUIDSeriesT) Set(v interface{}) Op {
+	m, ok := toMap(v)
+	if !ok {
+		panic("Can't set: not able to convert")
+	}
+	id, ok := m[o.idField].(gocql.UUID)
+	if !ok {
+		panic(fmt.Sprintf("Id field (%s) is not present or is not a gocql.UUID", o.idField))
+	}
+
+	timestamp, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+
+	m[bucketFieldName] = o.bucketing().Bucket(timestamp)
+
+	return o.Table().Set(m)
+}
+
+func (o *timeUUIDSeriesT) Update(id gocql.UUID, m map[string]interface{}) Op {
+	timestamp, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	bucket := o.bucketing().Bucket(timestamp)
+
+	return o.Table().
+		Where(Eq(bucketFieldName, bucket),
+			Eq(o.idField, id)).
+		Update(m)
+}
+
+func (o *timeUUIDSeriesT) UpdateStatic(timeStamp time.Time, m map[string]interface{}) Op {
+	return o.Buckets(timeStamp).Filter().Update(m)
+}
+
+func (o *timeUUIDSeriesT) Delete(id gocql.UUID) Op {
+	timestamp, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	bucket := o.bucketing().Bucket(timestamp)
+
+	return o.Table().
+		Where(Eq(bucketFieldName, bucket),
+			Eq(o.idField, id)).
+		Delete()
+}
+
+func (o *timeUUIDSeriesT) Read(id gocql.UUID, pointer interface{}) Op {
+	timestamp, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	bucket := o.bucketing().Bucket(timestamp)
+
+	return o.Table().
+		Where(Eq(bucketFieldName, bucket),
+			Eq(o.idField, id)).
+		ReadOne(pointer)
+}
+
+func (o *timeUUIDSeriesT) List(startTime, endTime time.Time, pointerToASlice interface{}) Op {
+	return newBucketsOp(o.Buckets(startTime), o.Buckets(endTime), endTime,
+		[]Relation{GTE(o.idField, minTimeUUID(startTime)), LT(o.idField, minTimeUUID(endTime))},
+		pointerToASlice)
+}
+
+func (o *timeUUIDSeriesT) bucketing() Bucketing {
+	return tableBucketing(o.Table(), o.bucketSize)
+}
+
+func (o *timeUUIDSeriesT) Buckets(start time.Time) Buckets {
+	return bucketIter{
+		v:         start,
+		bucketing: o.bucketing(),
+		field:     bucketFieldName,
+		invariant: o.Table().Where()}
+}
+
+func (o *timeUUIDSeriesT) Retention(ctx context.Context, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return retention.DeleteBefore(ctx, o.Buckets(start), cutoff)
+}
+
+func (o *timeUUIDSeriesT) ListSince(id gocql.UUID, window time.Duration, pointerToASlice interface{}) Op {
+	startTime, err := timeUUIDToTime(id)
+	if err != nil {
+		return errOp{err: err}
+	}
+	endTime := sinceEndTime(startTime, window)
+
+	return newBucketsOp(o.Buckets(startTime), o.Buckets(endTime), endTime,
+		[]Relation{GT(o.idField, id), LT(o.idField, minTimeUUID(endTime))},
+		pointerToASlice)
+}
+
+func (o *timeUUIDSeriesT) WithOptions(opt Options) TimeUUIDSeriesTable {
+	result := &timeUUIDSeriesT{
+		t:          o.Table().WithOptions(opt),
+		idField:    o.idField,
+		bucketSize: o.bucketSize}
+	reregister(o, result)
+	return result
+}
+
+func timeUUIDToTime(id gocql.UUID) (time.Time, error) {
+	if id.Version() != 1 {
+		return time.Time{}, errors.New("Invalid time UUID")
+	}
+	return id.Time(), nil
+}
+
+// minTimeUUID returns the smallest time UUID of the given time, like the
+// minTimeuuid function of CQL. Every byte of its clock sequence and node is
+// 0x80, the smallest signed byte.
+func minTimeUUID(t time.Time) gocql.UUID {
+	u := gocql.UUIDFromTime(t)
+	for i := 8; i < 16; i++ {
+		u[i] = 0x80
+	}
+	return u
+}
@@ -0,0 +1,82 @@
+package gocassa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/stretchr/testify/assert"
+)
+
+// Event doesn't tag its id as a timeuuid, the time UUID series recipes
+// declare it as one
+type Event struct {
+	Id   gocql.UUID `cql:"id"`
+	Kind string     `cql:"kind"`
+}
+
+func TestTimeUUIDSeriesTableStatements(t *testing.T) {
+	tbl := NewConnection(nil).KeySpace("ks1").TimeUUIDSeriesTable("event", "id", time.Hour, Event{})
+	assert.Equal(t, "event_timeUUIDSeries_id_1h0m0s", tbl.Name())
+
+	stmt, err := tbl.CreateStatement()
+	assert.NoError(t, err)
+	assert.Contains(t, stmt.Query(), "id timeuuid")
+	assert.Contains(t, stmt.Query(), "PRIMARY KEY ((bucket), id)")
+
+	events := []Event{}
+	start, end := parse("2006 Jan 2 15:00:00"), parse("2006 Jan 2 15:04:05")
+	stmt = tbl.List(start, end, &events).GenerateStatement()
+	assert.Equal(t, "SELECT bucket, id, kind FROM ks1.event_timeUUIDSeries_id_1h0m0s WHERE bucket IN ? AND id >= ? AND id < ?", stmt.Query())
+	assert.Equal(t, []interface{}{minTimeUUID(start), minTimeUUID(end)}, stmt.Values()[1:])
+
+	random, err := gocql.RandomUUID()
+	assert.NoError(t, err)
+	assert.EqualError(t, tbl.Read(random, &Event{}).Run(), "Invalid time UUID")
+}
+
+type userTimeUUIDEvent struct {
+	UserId string     `cql:"user_id"`
+	Id     gocql.UUID `cql:"id"`
+	Kind   string     `cql:"kind"`
+}
+
+func TestMultiKeyTimeUUIDSeriesTableStatements(t *testing.T) {
+	ks := NewConnection(nil).KeySpace("ks1")
+	tbl := ks.MultiKeyTimeUUIDSeriesTable("event", []string{"user_id"}, "id", time.Hour, userTimeUUIDEvent{})
+	stmt, err := tbl.CreateStatement()
+	assert.NoError(t, err)
+	assert.Contains(t, stmt.Query(), "id timeuuid")
+	assert.Contains(t, stmt.Query(), "PRIMARY KEY ((user_id, bucket), id)")
+
+	// The override only applies to the tables of the recipes
+	stmt, err = ks.Table("event", userTimeUUIDEvent{}, Keys{PartitionKeys: []string{"user_id"}, ClusteringColumns: []string{"id"}}).CreateStatement()
+	assert.NoError(t, err)
+	assert.Contains(t, stmt.Query(), "id uuid")
+}
+
+func TestCompareTimeUUIDs(t *testing.T) {
+	// Just before the low bits of the timestamp, which come first in the
+	// bytes of a UUID, wrap around
+	at := parse("2006 Jan 2 15:04:05")
+	low := binary.BigEndian.Uint32(gocql.UUIDFromTime(at).Bytes())
+	at = at.Add(time.Duration(math.MaxUint32-low) * 100)
+	earlier, later := gocql.UUIDFromTime(at), gocql.UUIDFromTime(at.Add(200))
+	assert.Equal(t, 1, bytes.Compare(earlier.Bytes(), later.Bytes()))
+	assert.Equal(t, -1, compareUUIDs(earlier, later))
+	assert.Equal(t, 1, compareUUIDs(later, earlier))
+
+	// The UUIDs of the same time are between the min and max time UUIDs of
+	// CQL, which compare their clock sequence and node as signed bytes
+	u := gocql.UUIDFromTime(at)
+	max := minTimeUUID(at)
+	for i := 8; i < 16; i++ {
+		max[i] = 0x7f
+	}
+	assert.Equal(t, -1, compareUUIDs(minTimeUUID(at), u))
+	assert.Equal(t, 1, compareUUIDs(max, u))
+	assert.Equal(t, 0, compareUUIDs(u, u))
+}
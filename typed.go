@@ -0,0 +1,248 @@
+package gocassa
+
+import (
+	"context"
+	"time"
+)
+
+// This file contains a typed layer on top of the recipe tables. The typed
+// tables wrap the untyped recipes so rows are read into values of the row
+// type T rather than through interface{} pointers, and keys are checked at
+// compile time.
+
+//
+// Map recipe
+//
+
+// TypedMapTable is a MapTable storing rows of type T keyed by values of
+// type K.
+type TypedMapTable[T any, K any] struct {
+	TableChanger
+	t MapTable
+}
+
+// NewMapTable returns a typed MapTable for rows of type T, see
+// KeySpace.MapTable.
+func NewMapTable[T any, K any](ks KeySpace, name, idField string) *TypedMapTable[T, K] {
+	return TypedMap[T, K](ks.MapTable(name, idField, new(T)))
+}
+
+// TypedMap wraps an existing MapTable whose rows are of type T.
+func TypedMap[T any, K any](t MapTable) *TypedMapTable[T, K] {
+	return &TypedMapTable[T, K]{TableChanger: t, t: t}
+}
+
+// Untyped returns the underlying MapTable.
+func (m *TypedMapTable[T, K]) Untyped() MapTable { return m.t }
+
+func (m *TypedMapTable[T, K]) Table() Table { return m.t.Table() }
+
+func (m *TypedMapTable[T, K]) WithOptions(o Options) *TypedMapTable[T, K] {
+	return TypedMap[T, K](m.t.WithOptions(o))
+}
+
+func (m *TypedMapTable[T, K]) Set(row T) Op {
+	return m.t.Set(row)
+}
+
+func (m *TypedMapTable[T, K]) Update(id K, valuesToUpdate map[string]interface{}) Op {
+	return m.t.Update(id, valuesToUpdate)
+}
+
+func (m *TypedMapTable[T, K]) Delete(id K) Op {
+	return m.t.Delete(id)
+}
+
+// Read returns the row with the given id, or a RowNotFoundError if there is
+// no such row.
+func (m *TypedMapTable[T, K]) Read(ctx context.Context, id K) (T, error) {
+	var row T
+	err := m.t.Read(id, &row).RunWithContext(ctx)
+	return row, err
+}
+
+// MultiRead returns the rows with the given ids, skipping the ones which
+// don't exist. With ParallelMultiReadQuery the rows are in the order of the
+// ids, and with MultiReadMissingKeys a KeysNotFoundError lists the ids which
+// don't exist.
+func (m *TypedMapTable[T, K]) MultiRead(ctx context.Context, ids ...K) ([]T, error) {
+	rows := []T{}
+	err := m.t.MultiRead(toInterfaces(ids), &rows).RunWithContext(ctx)
+	return rows, err
+}
+
+//
+// Multimap recipe
+//
+
+// TypedMultimapTable is a MultimapTable storing rows of type T which are
+// partitioned by values of type P and ordered within a partition by values
+// of type C.
+type TypedMultimapTable[T any, P any, C any] struct {
+	TableChanger
+	t MultimapTable
+}
+
+// NewMultimapTable returns a typed MultimapTable for rows of type T, see
+// KeySpace.MultimapTable.
+func NewMultimapTable[T any, P any, C any](ks KeySpace, name, partitionKey, clusteringKey string) *TypedMultimapTable[T, P, C] {
+	return TypedMultimap[T, P, C](ks.MultimapTable(name, partitionKey, clusteringKey, new(T)))
+}
+
+// TypedMultimap wraps an existing MultimapTable whose rows are of type T.
+func TypedMultimap[T any, P any, C any](t MultimapTable) *TypedMultimapTable[T, P, C] {
+	return &TypedMultimapTable[T, P, C]{TableChanger: t, t: t}
+}
+
+// Untyped returns the underlying MultimapTable.
+func (mm *TypedMultimapTable[T, P, C]) Untyped() MultimapTable { return mm.t }
+
+func (mm *TypedMultimapTable[T, P, C]) Table() Table { return mm.t.Table() }
+
+func (mm *TypedMultimapTable[T, P, C]) WithOptions(o Options) *TypedMultimapTable[T, P, C] {
+	return TypedMultimap[T, P, C](mm.t.WithOptions(o))
+}
+
+func (mm *TypedMultimapTable[T, P, C]) Set(row T) Op {
+	return mm.t.Set(row)
+}
+
+func (mm *TypedMultimapTable[T, P, C]) Update(value P, id C, valuesToUpdate map[string]interface{}) Op {
+	return mm.t.Update(value, id, valuesToUpdate)
+}
+
+func (mm *TypedMultimapTable[T, P, C]) UpdateStatic(value P, valuesToUpdate map[string]interface{}) Op {
+	return mm.t.UpdateStatic(value, valuesToUpdate)
+}
+
+func (mm *TypedMultimapTable[T, P, C]) Delete(value P, id C) Op {
+	return mm.t.Delete(value, id)
+}
+
+func (mm *TypedMultimapTable[T, P, C]) DeleteAll(value P) Op {
+	return mm.t.DeleteAll(value)
+}
+
+// Read returns a single row, or a RowNotFoundError if there is no such row.
+func (mm *TypedMultimapTable[T, P, C]) Read(ctx context.Context, value P, id C) (T, error) {
+	var row T
+	err := mm.t.Read(value, id, &row).RunWithContext(ctx)
+	return row, err
+}
+
+// MultiRead returns the rows of a partition with the given ids, skipping the
+// ones which don't exist. With ParallelMultiReadQuery the rows are in the
+// order of the ids, and with MultiReadMissingKeys a KeysNotFoundError lists
+// the ids which don't exist.
+func (mm *TypedMultimapTable[T, P, C]) MultiRead(ctx context.Context, value P, ids ...C) ([]T, error) {
+	rows := []T{}
+	err := mm.t.MultiRead(value, toInterfaces(ids), &rows).RunWithContext(ctx)
+	return rows, err
+}
+
+// List returns up to limit rows of a partition (all of them if limit is 0),
+// starting from startId if it isn't nil.
+func (mm *TypedMultimapTable[T, P, C]) List(ctx context.Context, value P, startId *C, limit int) ([]T, error) {
+	var start interface{}
+	if startId != nil {
+		start = *startId
+	}
+	rows := []T{}
+	err := mm.t.List(value, start, limit, &rows).RunWithContext(ctx)
+	return rows, err
+}
+
+// ListRange returns up to limit rows of a partition (all of them if limit is
+// 0) with ids in the given range.
+func (mm *TypedMultimapTable[T, P, C]) ListRange(ctx context.Context, value P, r IDRange, limit int) ([]T, error) {
+	rows := []T{}
+	err := mm.t.ListRange(value, r, limit, &rows).RunWithContext(ctx)
+	return rows, err
+}
+
+//
+// TimeSeries recipe
+//
+
+// TypedTimeSeriesTable is a TimeSeriesTable storing rows of type T which are
+// identified by values of type I within a bucket.
+type TypedTimeSeriesTable[T any, I any] struct {
+	TableChanger
+	t TimeSeriesTable
+}
+
+// NewTimeSeriesTable returns a typed TimeSeriesTable for rows of type T, see
+// KeySpace.TimeSeriesTable.
+func NewTimeSeriesTable[T any, I any](ks KeySpace, name, timeField, idField string, bucketSize time.Duration) *TypedTimeSeriesTable[T, I] {
+	return TypedTimeSeries[T, I](ks.TimeSeriesTable(name, timeField, idField, bucketSize, new(T)))
+}
+
+// TypedTimeSeries wraps an existing TimeSeriesTable whose rows are of type T.
+func TypedTimeSeries[T any, I any](t TimeSeriesTable) *TypedTimeSeriesTable[T, I] {
+	return &TypedTimeSeriesTable[T, I]{TableChanger: t, t: t}
+}
+
+// Untyped returns the underlying TimeSeriesTable.
+func (o *TypedTimeSeriesTable[T, I]) Untyped() TimeSeriesTable { return o.t }
+
+func (o *TypedTimeSeriesTable[T, I]) Table() Table { return o.t.Table() }
+
+func (o *TypedTimeSeriesTable[T, I]) WithOptions(opts Options) *TypedTimeSeriesTable[T, I] {
+	return TypedTimeSeries[T, I](o.t.WithOptions(opts))
+}
+
+func (o *TypedTimeSeriesTable[T, I]) Set(row T) Op {
+	return o.t.Set(row)
+}
+
+func (o *TypedTimeSeriesTable[T, I]) Update(timeStamp time.Time, id I, valuesToUpdate map[string]interface{}) Op {
+	return o.t.Update(timeStamp, id, valuesToUpdate)
+}
+
+func (o *TypedTimeSeriesTable[T, I]) UpdateStatic(timeStamp time.Time, valuesToUpdate map[string]interface{}) Op {
+	return o.t.UpdateStatic(timeStamp, valuesToUpdate)
+}
+
+func (o *TypedTimeSeriesTable[T, I]) Delete(timeStamp time.Time, id I) Op {
+	return o.t.Delete(timeStamp, id)
+}
+
+// Read returns a single row, or a RowNotFoundError if there is no such row.
+func (o *TypedTimeSeriesTable[T, I]) Read(ctx context.Context, timeStamp time.Time, id I) (T, error) {
+	var row T
+	err := o.t.Read(timeStamp, id, &row).RunWithContext(ctx)
+	return row, err
+}
+
+// List returns the rows with a time between start and end.
+func (o *TypedTimeSeriesTable[T, I]) List(ctx context.Context, start, end time.Time) ([]T, error) {
+	rows := []T{}
+	err := o.t.List(start, end, &rows).RunWithContext(ctx)
+	return rows, err
+}
+
+// ListLatest returns at most limit rows with a time before the given time,
+// but not before start, newest first.
+func (o *TypedTimeSeriesTable[T, I]) ListLatest(ctx context.Context, start, before time.Time, limit int) ([]T, error) {
+	rows := []T{}
+	err := o.t.ListLatest(start, before, limit, &rows).RunWithContext(ctx)
+	return rows, err
+}
+
+func (o *TypedTimeSeriesTable[T, I]) Buckets(start time.Time) Buckets {
+	return o.t.Buckets(start)
+}
+
+// Retention deletes the buckets from the one of start up to the last one
+// ending before or at cutoff, see BucketRetention.
+func (o *TypedTimeSeriesTable[T, I]) Retention(ctx context.Context, start, cutoff time.Time, retention BucketRetention) ([]time.Time, error) {
+	return o.t.Retention(ctx, start, cutoff, retention)
+}
+
+func toInterfaces[K any](keys []K) []interface{} {
+	result := make([]interface{}, len(keys))
+	for i, k := range keys {
+		result[i] = k
+	}
+	return result
+}
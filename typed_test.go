@@ -0,0 +1,90 @@
+package gocassa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedSale struct {
+	Id       string
+	SellerId string
+	Price    int
+	Created  time.Time
+}
+
+func TestTypedMapTable(t *testing.T) {
+	ctx := context.Background()
+	tbl := NewMapTable[typedSale, string](NewMockKeySpace(), "sales", "Id")
+	require.NoError(t, tbl.Set(typedSale{Id: "1", Price: 10}).Run())
+	require.NoError(t, tbl.Set(typedSale{Id: "2", Price: 20}).Run())
+	require.NoError(t, tbl.Update("2", map[string]interface{}{"Price": 25}).Run())
+
+	sale, err := tbl.Read(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, typedSale{Id: "2", Price: 25}, sale)
+
+	sales, err := tbl.MultiRead(ctx, "1", "2", "3")
+	require.NoError(t, err)
+	assert.Len(t, sales, 2)
+
+	require.NoError(t, tbl.Delete("1").Run())
+	_, err = tbl.Read(ctx, "1")
+	assert.IsType(t, RowNotFoundError{}, err)
+	assert.Equal(t, "sales_map_Id", tbl.Name())
+}
+
+func TestTypedMultimapTable(t *testing.T) {
+	ctx := context.Background()
+	tbl := NewMultimapTable[typedSale, string, string](NewMockKeySpace(), "sales", "SellerId", "Id")
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, tbl.Set(typedSale{Id: id, SellerId: "s1"}).Run())
+	}
+
+	sale, err := tbl.Read(ctx, "s1", "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", sale.Id)
+
+	start := "b"
+	sales, err := tbl.List(ctx, "s1", &start, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []typedSale{{Id: "b", SellerId: "s1"}, {Id: "c", SellerId: "s1"}}, sales)
+
+	sales, err = tbl.List(ctx, "s1", nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []typedSale{{Id: "a", SellerId: "s1"}}, sales)
+
+	sales, err = tbl.MultiRead(ctx, "s1", "a", "c")
+	require.NoError(t, err)
+	assert.Len(t, sales, 2)
+
+	require.NoError(t, tbl.DeleteAll("s1").Run())
+	sales, err = tbl.List(ctx, "s1", nil, 0)
+	require.NoError(t, err)
+	assert.Empty(t, sales)
+}
+
+func TestTypedTimeSeriesTable(t *testing.T) {
+	ctx := context.Background()
+	tbl := NewTimeSeriesTable[typedSale, string](NewMockKeySpace(), "sales", "Created", "Id", time.Hour)
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c"} {
+		require.NoError(t, tbl.Set(typedSale{Id: id, Created: start.Add(time.Duration(i) * 40 * time.Minute)}).Run())
+	}
+
+	sale, err := tbl.Read(ctx, start.Add(40*time.Minute), "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", sale.Id)
+
+	sales, err := tbl.List(ctx, start, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, sales, 2)
+
+	// The untyped API keeps working on the same table
+	var untyped []typedSale
+	require.NoError(t, tbl.Untyped().List(start, start.Add(2*time.Hour), &untyped).Run())
+	assert.Len(t, untyped, 3)
+}
@@ -0,0 +1,440 @@
+package gocassa
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	r "github.com/rkilburn/gocassa/reflect"
+)
+
+// Nested structs are stored as Cassandra user-defined types (UDTs), or tuples
+// when tagged as such. gocql can only match UDT fields against struct fields
+// with an exact `cql` tag, so the gocql backend wraps any value containing a
+// nested struct with the codecs in this file, which resolve field names in the
+// same way as the rest of gocassa.
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	durationType    = reflect.TypeOf(gocql.Duration{})
+	byteSliceType   = reflect.TypeOf([]byte{})
+	ipType          = reflect.TypeOf(net.IP{})
+	marshalerType   = reflect.TypeOf((*gocql.Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*gocql.Unmarshaler)(nil)).Elem()
+)
+
+// isUDTType returns whether values of the given type are stored as a
+// user-defined type
+func isUDTType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || typ == timeType || typ == durationType {
+		return false
+	}
+	ptrTyp := reflect.PtrTo(typ)
+	return !typ.Implements(marshalerType) &&
+		!ptrTyp.Implements(marshalerType) &&
+		!ptrTyp.Implements(unmarshalerType)
+}
+
+// udtName returns the name of the user-defined type for a struct type
+func udtName(typ reflect.Type) string {
+	return strings.ToLower(typ.Name())
+}
+
+// needsUDTCodec returns whether the type is, or contains, a user-defined type
+func needsUDTCodec(typ reflect.Type) bool {
+	switch {
+	case isUDTType(typ):
+		return true
+	case typ == byteSliceType:
+		return false
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		return needsUDTCodec(typ.Elem())
+	case typ.Kind() == reflect.Map:
+		return needsUDTCodec(typ.Key()) || needsUDTCodec(typ.Elem())
+	}
+	return false
+}
+
+// valueNeedsUDTCodec is like needsUDTCodec but also inspects the dynamic
+// values held within interface{} slices and maps, such as modifier arguments
+func valueNeedsUDTCodec(v reflect.Value) bool {
+	if !v.IsValid() {
+		return false
+	}
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		return valueNeedsUDTCodec(v.Elem())
+	}
+	if needsUDTCodec(v.Type()) {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Interface {
+			return false
+		}
+		for i := 0; i < v.Len(); i++ {
+			if valueNeedsUDTCodec(v.Index(i)) {
+				return true
+			}
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.Interface {
+			return false
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if valueNeedsUDTCodec(iter.Value()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// udtMarshaler wraps a bind value containing user-defined types
+type udtMarshaler struct {
+	value interface{}
+}
+
+func (u udtMarshaler) MarshalCQL(info gocql.TypeInfo) ([]byte, error) {
+	return marshalUDTValue(info, reflect.ValueOf(u.value))
+}
+
+// udtUnmarshaler wraps a scan destination pointer containing user-defined types
+type udtUnmarshaler struct {
+	dest reflect.Value
+}
+
+func (u udtUnmarshaler) UnmarshalCQL(info gocql.TypeInfo, data []byte) error {
+	return unmarshalUDTValue(info, data, u.dest.Elem())
+}
+
+// wrapUDTValues wraps any bind values which contain user-defined types so they
+// are marshalled with gocassa's field naming rules
+func wrapUDTValues(values []interface{}) []interface{} {
+	var result []interface{}
+	for i, v := range values {
+		if !valueNeedsUDTCodec(reflect.ValueOf(v)) {
+			continue
+		}
+		if result == nil {
+			result = make([]interface{}, len(values))
+			copy(result, values)
+		}
+		result[i] = udtMarshaler{value: v}
+	}
+	if result == nil {
+		return values
+	}
+	return result
+}
+
+// udtScannable wraps a Scannable so scan destinations containing user-defined
+// types are unmarshalled with gocassa's field naming rules
+type udtScannable struct {
+	Scannable
+}
+
+func (s udtScannable) Scan(dest ...interface{}) error {
+	wrapped := make([]interface{}, len(dest))
+	for i, d := range dest {
+		wrapped[i] = d
+		if rv := reflect.ValueOf(d); rv.Kind() == reflect.Ptr && needsUDTCodec(rv.Type().Elem()) {
+			wrapped[i] = udtUnmarshaler{dest: rv}
+		}
+	}
+	return s.Scannable.Scan(wrapped...)
+}
+
+func marshalUDTValue(info gocql.TypeInfo, v reflect.Value) ([]byte, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	switch info := info.(type) {
+	case gocql.UDTTypeInfo:
+		if !isUDTType(v.Type()) {
+			break
+		}
+		fieldMap, err := r.StructFieldMap(v.Type(), true)
+		if err != nil {
+			return nil, err
+		}
+		var buf []byte
+		for _, e := range info.Elements {
+			var data []byte
+			if f, ok := fieldMap[strings.ToLower(e.Name)]; ok {
+				if fv, ok := udtFieldByIndex(v, f.Index()); ok {
+					if data, err = marshalUDTValue(e.Type, fv); err != nil {
+						return nil, err
+					}
+				}
+			}
+			buf = appendCQLBytes(buf, 4, data)
+		}
+		return buf, nil
+	case gocql.TupleTypeInfo:
+		if !isUDTType(v.Type()) {
+			break
+		}
+		fields, err := r.StructFields(v.Type())
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) != len(info.Elems) {
+			return nil, fmt.Errorf("can not marshal %v into %v: have %d fields want %d", v.Type(), info, len(fields), len(info.Elems))
+		}
+		var buf []byte
+		for i, f := range fields {
+			var data []byte
+			if fv, ok := udtFieldByIndex(v, f.Index()); ok {
+				if data, err = marshalUDTValue(info.Elems[i], fv); err != nil {
+					return nil, err
+				}
+			}
+			buf = appendCQLBytes(buf, 4, data)
+		}
+		return buf, nil
+	case gocql.CollectionType:
+		size := collectionSizeLen(info)
+		switch {
+		case v.Kind() == reflect.Map:
+			if v.IsNil() {
+				return nil, nil
+			}
+			buf := appendCQLSize(nil, size, v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				key, err := marshalUDTValue(info.Key, iter.Key())
+				if err != nil {
+					return nil, err
+				}
+				elem, err := marshalUDTValue(info.Elem, iter.Value())
+				if err != nil {
+					return nil, err
+				}
+				buf = appendCQLBytes(buf, size, key)
+				buf = appendCQLBytes(buf, size, elem)
+			}
+			return buf, nil
+		case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type() != byteSliceType:
+			if v.Kind() == reflect.Slice && v.IsNil() {
+				return nil, nil
+			}
+			buf := appendCQLSize(nil, size, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				elem, err := marshalUDTValue(info.Elem, v.Index(i))
+				if err != nil {
+					return nil, err
+				}
+				buf = appendCQLBytes(buf, size, elem)
+			}
+			return buf, nil
+		}
+	}
+	return gocql.Marshal(info, v.Interface())
+}
+
+func unmarshalUDTValue(info gocql.TypeInfo, data []byte, v reflect.Value) error {
+	switch info := info.(type) {
+	case gocql.UDTTypeInfo:
+		if !isUDTType(v.Type()) {
+			break
+		}
+		v.Set(reflect.Zero(v.Type()))
+		fieldMap, err := r.StructFieldMap(v.Type(), true)
+		if err != nil {
+			return err
+		}
+		for _, e := range info.Elements {
+			// Fields added to the type after the value was written are not present
+			if len(data) == 0 {
+				return nil
+			}
+			var p []byte
+			if p, data, err = readCQLBytes(data, 4); err != nil {
+				return err
+			}
+			f, ok := fieldMap[strings.ToLower(e.Name)]
+			if !ok {
+				continue
+			}
+			if err := unmarshalUDTValue(e.Type, p, allocFieldByIndex(v, f.Index())); err != nil {
+				return err
+			}
+		}
+		return nil
+	case gocql.TupleTypeInfo:
+		if !isUDTType(v.Type()) {
+			break
+		}
+		v.Set(reflect.Zero(v.Type()))
+		fields, err := r.StructFields(v.Type())
+		if err != nil {
+			return err
+		}
+		if len(fields) != len(info.Elems) {
+			return fmt.Errorf("can not unmarshal %v into %v: have %d fields want %d", info, v.Type(), len(fields), len(info.Elems))
+		}
+		for i, f := range fields {
+			if len(data) == 0 {
+				return nil
+			}
+			var p []byte
+			if p, data, err = readCQLBytes(data, 4); err != nil {
+				return err
+			}
+			if err := unmarshalUDTValue(info.Elems[i], p, allocFieldByIndex(v, f.Index())); err != nil {
+				return err
+			}
+		}
+		return nil
+	case gocql.CollectionType:
+		size := collectionSizeLen(info)
+		switch {
+		case v.Kind() == reflect.Map:
+			if data == nil {
+				v.Set(reflect.Zero(v.Type()))
+				return nil
+			}
+			n, data, err := readCQLSize(data, size)
+			if err != nil {
+				return err
+			}
+			m := reflect.MakeMapWithSize(v.Type(), n)
+			for i := 0; i < n; i++ {
+				var kp, ep []byte
+				if kp, data, err = readCQLBytes(data, size); err != nil {
+					return err
+				}
+				if ep, data, err = readCQLBytes(data, size); err != nil {
+					return err
+				}
+				key := reflect.New(v.Type().Key()).Elem()
+				if err := unmarshalUDTValue(info.Key, kp, key); err != nil {
+					return err
+				}
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := unmarshalUDTValue(info.Elem, ep, elem); err != nil {
+					return err
+				}
+				m.SetMapIndex(key, elem)
+			}
+			v.Set(m)
+			return nil
+		case v.Kind() == reflect.Slice && v.Type() != byteSliceType:
+			if data == nil {
+				v.Set(reflect.Zero(v.Type()))
+				return nil
+			}
+			n, data, err := readCQLSize(data, size)
+			if err != nil {
+				return err
+			}
+			s := reflect.MakeSlice(v.Type(), n, n)
+			for i := 0; i < n; i++ {
+				var p []byte
+				if p, data, err = readCQLBytes(data, size); err != nil {
+					return err
+				}
+				if err := unmarshalUDTValue(info.Elem, p, s.Index(i)); err != nil {
+					return err
+				}
+			}
+			v.Set(s)
+			return nil
+		}
+	}
+	return gocql.Unmarshal(info, data, v.Addr().Interface())
+}
+
+// udtFieldByIndex returns the nested field for a struct value, returning false
+// if the field is within a nil embedded pointer
+func udtFieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v, true
+}
+
+// allocFieldByIndex returns the nested field for a struct value, allocating
+// any nil embedded pointers along the way
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+// collectionSizeLen returns the number of bytes used to encode sizes within
+// a collection, which depends on the protocol version
+func collectionSizeLen(info gocql.CollectionType) int {
+	if info.Version() > 0x02 {
+		return 4
+	}
+	return 2
+}
+
+func appendCQLSize(buf []byte, sizeLen, n int) []byte {
+	if sizeLen == 2 {
+		return append(buf, byte(n>>8), byte(n))
+	}
+	return append(buf, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func appendCQLBytes(buf []byte, sizeLen int, data []byte) []byte {
+	if data == nil {
+		return appendCQLSize(buf, sizeLen, -1)
+	}
+	return append(appendCQLSize(buf, sizeLen, len(data)), data...)
+}
+
+func readCQLSize(data []byte, sizeLen int) (int, []byte, error) {
+	if len(data) < sizeLen {
+		return 0, nil, fmt.Errorf("unexpected eof reading size")
+	}
+	if sizeLen == 2 {
+		return int(binary.BigEndian.Uint16(data)), data[2:], nil
+	}
+	return int(int32(binary.BigEndian.Uint32(data))), data[4:], nil
+}
+
+func readCQLBytes(data []byte, sizeLen int) ([]byte, []byte, error) {
+	n, data, err := readCQLSize(data, sizeLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n < 0 {
+		return nil, data, nil
+	}
+	if len(data) < n {
+		return nil, nil, fmt.Errorf("unexpected eof reading %d bytes", n)
+	}
+	return data[:n], data[n:], nil
+}